@@ -4,31 +4,112 @@
 //
 // It is meant to be used by maintainers in conjunction with the go generate tool
 // to update the list.
+//
+// Usage:
+//
+//	go run cmd/gen/gen.go [flags]
+//
+// The flags are:
+//
+//	-source string
+//		URL or path of the PSL to compile, instead of the latest version on GitHub
+//	-version string
+//		commit SHA of the PSL, instead of the COMMIT header
+//	-date string
+//		date of the PSL in RFC 3339 format, instead of the VERSION header
+//	-output string
+//		path of the generated file (default "rules.go")
+//	-package string
+//		name of the package of the generated file (default "publicsuffix")
+//	-func string
+//		name of the function that returns the rules (default "DefaultRules")
+//	-rules-var string
+//		name of the variable that holds the rules (default "r")
+//	-owners-var string
+//		name of the variable that holds the rule owners (default "o")
+//	-list-var string
+//		name of the List variable the rules are added to (default "DefaultList")
+//	-dry-run
+//		print the generated code to stdout instead of writing the file
+//	-check
+//		exit with a non-zero status if the generated file is out of date
+//	-v
+//		enable verbose output
 package main
 
 import (
 	"context"
+	"errors"
+	"flag"
 	"fmt"
 	"os"
 	"os/signal"
+	"strings"
+	"time"
 
 	"github.com/weppos/publicsuffix-go/publicsuffix/generator"
 )
 
-const (
-	// where the rules will be written
-	filename = "rules.go"
+var (
+	source    = flag.String("source", "", "URL or path of the PSL to compile, instead of the latest version on GitHub")
+	version   = flag.String("version", "", "commit SHA of the PSL, instead of the COMMIT header")
+	date      = flag.String("date", "", "date of the PSL in RFC 3339 format, instead of the VERSION header")
+	output    = flag.String("output", "rules.go", "path of the generated file")
+	pkg       = flag.String("package", "publicsuffix", "name of the package of the generated file")
+	rulesFunc = flag.String("func", "DefaultRules", "name of the function that returns the rules")
+	rulesVar  = flag.String("rules-var", "r", "name of the variable that holds the rules")
+	ownersVar = flag.String("owners-var", "o", "name of the variable that holds the rule owners")
+	listVar   = flag.String("list-var", "DefaultList", "name of the List variable the rules are added to")
+	dryRun    = flag.Bool("dry-run", false, "print the generated code to stdout instead of writing the file")
+	check     = flag.Bool("check", false, "exit with a non-zero status if the generated file is out of date")
+	verbose   = flag.Bool("v", false, "enable verbose output")
 )
 
 func main() {
+	flag.Parse()
+
 	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
 	defer cancel()
 
 	g := generator.NewGenerator()
-	g.Verbose = true
-	err := g.Write(ctx, filename)
+	g.Verbose = *verbose
+	if strings.HasPrefix(*source, "http://") || strings.HasPrefix(*source, "https://") {
+		g.SourceURL = *source
+	} else {
+		g.SourceFile = *source
+	}
+	g.VersionSHA = *version
+	if *date != "" {
+		t, err := time.Parse(time.RFC3339, *date)
+		if err != nil {
+			exit(fmt.Errorf("invalid date: %w", err))
+		}
+		g.VersionDate = t
+	}
+	g.Package = *pkg
+	g.RulesFunc = *rulesFunc
+	g.RulesVar = *rulesVar
+	g.OwnersVar = *ownersVar
+	g.ListVar = *listVar
+
+	var err error
+	switch {
+	case *check:
+		err = g.Check(ctx, *output)
+	case *dryRun:
+		err = g.Print(ctx)
+	default:
+		err = g.Write(ctx, *output)
+	}
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
+		exit(err)
+	}
+}
+
+func exit(err error) {
+	fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+	if errors.Is(err, generator.ErrOutOfDate) {
+		os.Exit(2)
 	}
+	os.Exit(1)
 }
@@ -4,17 +4,30 @@ package main
 
 import (
 	"fmt"
+	"runtime"
 	"time"
 
 	"github.com/weppos/publicsuffix-go/publicsuffix"
 )
 
+func heap() uint64 {
+	var m runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&m)
+	return m.HeapAlloc
+}
+
 func main() {
+	// The DefaultList is loaded lazily, so the heap measured here
+	// is the cost paid by programs that import the package but never use it.
+	fmt.Printf("Heap before load: %d KB\n", heap()/1024)
+
 	startTime := time.Now()
 	defer func() {
 		elapsed := time.Since(startTime)
 		elapsed -= elapsed % 1000000
 		fmt.Printf("Time elapsed: %s\n", elapsed)
+		fmt.Printf("Heap after load: %d KB\n", heap()/1024)
 	}()
 
 	fmt.Printf("%d rules loaded\n", publicsuffix.DefaultList.Size())
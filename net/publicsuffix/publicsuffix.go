@@ -6,34 +6,40 @@ import (
 	psl "github.com/weppos/publicsuffix-go/publicsuffix"
 )
 
+// These errors are returned by EffectiveTLDPlusOne wrapped in a *ParseError,
+// and they can be checked with errors.Is.
+// They are the same values defined in the publicsuffix package.
+var (
+	ErrBlankName      = psl.ErrBlankName
+	ErrLeadingDot     = psl.ErrLeadingDot
+	ErrInvalidIDN     = psl.ErrInvalidIDN
+	ErrIPAddress      = psl.ErrIPAddress
+	ErrIsPublicSuffix = psl.ErrIsPublicSuffix
+	ErrNoRuleMatch    = psl.ErrNoRuleMatch
+)
+
+// ParseError is the error returned by EffectiveTLDPlusOne
+// when the domain cannot be parsed.
+type ParseError = psl.ParseError
+
 // PublicSuffix returns the public suffix of the domain
 // using a copy of the publicsuffix.org database packaged into this library.
 //
 // Note. To maintain compatibility with the golang.org/x/net/publicsuffix
 // this method doesn't return an error. However, in case of error,
 // the returned value is empty.
+//
+// Unlike golang.org/x/net/publicsuffix, the root dot of a fully-qualified domain
+// is ignored: the public suffix of "www.example.com." is "com".
 func PublicSuffix(domain string) (publicSuffix string, icann bool) {
-	//d, err := psl.Parse(domain)
-	//if err != nil {
-	//	return "", false
-	//}
-	//
-	//return d.Rule.Value, !d.Rule.Private
-
-	rule := psl.DefaultList.Find(domain, nil)
-	publicSuffix = rule.Decompose(domain)[1]
-	icann = !rule.Private
-
-	// x/net/publicsuffix sets icann to false when the default rule "*" is used
-	if rule.Value == "" && rule.Type == psl.WildcardType {
-		icann = false
-	}
-
+	publicSuffix, icann, _ = psl.DefaultList.PublicSuffix(domain)
 	return
 }
 
 // EffectiveTLDPlusOne returns the effective top level domain plus one more label.
 // For example, the eTLD+1 for "foo.bar.golang.org" is "golang.org".
+//
+// If the domain cannot be parsed, the error is a *ParseError.
 func EffectiveTLDPlusOne(domain string) (string, error) {
 	return psl.Domain(domain)
 }
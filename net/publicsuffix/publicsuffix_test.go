@@ -1,6 +1,7 @@
 package publicsuffix_test
 
 import (
+	"errors"
 	"testing"
 
 	wpsl "github.com/weppos/publicsuffix-go/net/publicsuffix"
@@ -17,8 +18,13 @@ func TestPublicSuffix(t *testing.T) {
 		"www.example.blogspot.com",
 		"parliament.uk",
 		"www.parliament.uk",
+		// suffixes
+		"com",
+		"co.uk",
+		"blogspot.com",
 		// not listed
 		"www.example.test",
+		"test",
 	}
 
 	for _, testCase := range testCases {
@@ -31,6 +37,32 @@ func TestPublicSuffix(t *testing.T) {
 	}
 }
 
+func TestPublicSuffix_FQDN(t *testing.T) {
+	testCases := []string{
+		"www.example.com",
+		"www.example.co.uk",
+		"www.example.blogspot.com",
+		"www.parliament.uk",
+		"www.example.test",
+	}
+
+	for _, testCase := range testCases {
+		ws, wb := wpsl.PublicSuffix(testCase + ".")
+		xs, xb := xpsl.PublicSuffix(testCase)
+
+		if ws != xs || wb != xb {
+			t.Errorf("PublicSuffix(%v.): x/psl(%v) -> (%v, %v) != w/psl -> (%v, %v)", testCase, testCase, xs, xb, ws, wb)
+		}
+
+		wd, we := wpsl.EffectiveTLDPlusOne(testCase + ".")
+		xd, xe := xpsl.EffectiveTLDPlusOne(testCase)
+
+		if wd != xd || we != xe {
+			t.Errorf("EffectiveTLDPlusOne(%v.): x/psl(%v) -> (%v, %v) != w/psl -> (%v, %v)", testCase, testCase, xd, xe, wd, we)
+		}
+	}
+}
+
 func TestEffectiveTLDPlusOne(t *testing.T) {
 	testCases := []string{
 		"example.com",
@@ -54,3 +86,25 @@ func TestEffectiveTLDPlusOne(t *testing.T) {
 		}
 	}
 }
+
+func TestEffectiveTLDPlusOne_Errors(t *testing.T) {
+	testCases := map[string]error{
+		"":             wpsl.ErrBlankName,
+		".example.com": wpsl.ErrLeadingDot,
+		"co.uk":        wpsl.ErrIsPublicSuffix,
+		"192.168.0.1":  wpsl.ErrIPAddress,
+		"[::1]":        wpsl.ErrIPAddress,
+	}
+
+	for input, want := range testCases {
+		_, err := wpsl.EffectiveTLDPlusOne(input)
+		if !errors.Is(err, want) {
+			t.Errorf("EffectiveTLDPlusOne(%v) error = %v, want %v", input, err, want)
+		}
+
+		var perr *wpsl.ParseError
+		if !errors.As(err, &perr) {
+			t.Errorf("EffectiveTLDPlusOne(%v) error %T is not a *ParseError", input, err)
+		}
+	}
+}
@@ -1,6 +1,9 @@
 package publicsuffix
 
 import (
+	"errors"
+	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -10,22 +13,24 @@ type validTestCase struct {
 	parsed *DomainName
 }
 
-func TestValid(t *testing.T) {
-	testCases := []validTestCase{
-		{"example.com", "example.com", &DomainName{"com", "example", "", MustNewRule("com")}},
-		{"foo.example.com", "example.com", &DomainName{"com", "example", "foo", MustNewRule("com")}},
+var validTestCases = []validTestCase{
+	{"example.com", "example.com", &DomainName{TLD: "com", SLD: "example", Rule: MustNewRule("com")}},
+	{"foo.example.com", "example.com", &DomainName{TLD: "com", SLD: "example", TRD: "foo", Rule: MustNewRule("com")}},
 
-		{"verybritish.co.uk", "verybritish.co.uk", &DomainName{"co.uk", "verybritish", "", MustNewRule("*.uk")}},
-		{"foo.verybritish.co.uk", "verybritish.co.uk", &DomainName{"co.uk", "verybritish", "foo", MustNewRule("*.uk")}},
+	{"verybritish.co.uk", "verybritish.co.uk", &DomainName{TLD: "co.uk", SLD: "verybritish", Rule: MustNewRule("*.uk")}},
+	{"foo.verybritish.co.uk", "verybritish.co.uk", &DomainName{TLD: "co.uk", SLD: "verybritish", TRD: "foo", Rule: MustNewRule("*.uk")}},
 
-		{"parliament.uk", "parliament.uk", &DomainName{"uk", "parliament", "", MustNewRule("!parliament.uk")}},
-		{"foo.parliament.uk", "parliament.uk", &DomainName{"uk", "parliament", "foo", MustNewRule("!parliament.uk")}},
+	{"parliament.uk", "parliament.uk", &DomainName{TLD: "uk", SLD: "parliament", Rule: MustNewRule("!parliament.uk")}},
+	{"foo.parliament.uk", "parliament.uk", &DomainName{TLD: "uk", SLD: "parliament", TRD: "foo", Rule: MustNewRule("!parliament.uk")}},
 
-		{"foo.blogspot.com", "foo.blogspot.com", &DomainName{"blogspot.com", "foo", "", MustNewRule("blogspot.com")}},
-		{"bar.foo.blogspot.com", "foo.blogspot.com", &DomainName{"blogspot.com", "foo", "bar", MustNewRule("blogspot.com")}},
-	}
+	{"foo.blogspot.com", "foo.blogspot.com", &DomainName{TLD: "blogspot.com", SLD: "foo", Rule: MustNewRule("blogspot.com")}},
+	{"bar.foo.blogspot.com", "foo.blogspot.com", &DomainName{TLD: "blogspot.com", SLD: "foo", TRD: "bar", Rule: MustNewRule("blogspot.com")}},
 
-	for _, testCase := range testCases {
+	{"a.b.foo.example.com", "example.com", &DomainName{TLD: "com", SLD: "example", TRD: "a.b.foo", Rule: MustNewRule("com")}},
+}
+
+func TestValid(t *testing.T) {
+	for _, testCase := range validTestCases {
 		got, err := Parse(testCase.input)
 		if err != nil {
 			t.Errorf("TestValid(%v) returned error: %v", testCase.input, err)
@@ -44,6 +49,133 @@ func TestValid(t *testing.T) {
 	}
 }
 
+func TestValid_DomainNameMethods(t *testing.T) {
+	for _, testCase := range validTestCases {
+		dn, err := Parse(testCase.input)
+		if err != nil {
+			t.Fatalf("Parse(%v) returned error: %v", testCase.input, err)
+		}
+
+		if got, want := dn.Labels(), strings.Split(testCase.input, "."); !reflect.DeepEqual(got, want) {
+			t.Errorf("Parse(%v).Labels() = %v, want %v", testCase.input, got, want)
+		}
+		if got, want := dn.Registrable(), testCase.domain; got != want {
+			t.Errorf("Parse(%v).Registrable() = %v, want %v", testCase.input, got, want)
+		}
+		if got, want := dn.Suffix(), testCase.parsed.TLD; got != want {
+			t.Errorf("Parse(%v).Suffix() = %v, want %v", testCase.input, got, want)
+		}
+		if got, want := dn.Private(), testCase.parsed.TLD == "blogspot.com"; got != want {
+			t.Errorf("Parse(%v).Private() = %v, want %v", testCase.input, got, want)
+		}
+		if got, want := dn.ICANN(), !dn.Private(); got != want {
+			t.Errorf("Parse(%v).ICANN() = %v, want %v", testCase.input, got, want)
+		}
+
+		depth := 0
+		if testCase.parsed.TRD != "" {
+			depth = len(strings.Split(testCase.parsed.TRD, "."))
+		}
+		if got := dn.Depth(); got != depth {
+			t.Errorf("Parse(%v).Depth() = %v, want %v", testCase.input, got, depth)
+		}
+
+		// walk up to the registrable domain
+		parent := dn
+		for i := depth; i > 0; i-- {
+			parent = parent.Parent()
+			if got := parent.Depth(); got != i-1 {
+				t.Errorf("Parse(%v).Parent().Depth() = %v, want %v", testCase.input, got, i-1)
+			}
+		}
+		if got, want := parent.String(), testCase.domain; got != want {
+			t.Errorf("Parse(%v) top Parent() = %v, want %v", testCase.input, got, want)
+		}
+		if got := parent.Parent(); got != nil {
+			t.Errorf("Parse(%v) top Parent().Parent() = %v, want nil", testCase.input, got)
+		}
+
+		sub, err := dn.Subdomain("x")
+		if err != nil {
+			t.Fatalf("Parse(%v).Subdomain(x) returned error: %v", testCase.input, err)
+		}
+		if got, want := sub.String(), "x."+testCase.input; got != want {
+			t.Errorf("Parse(%v).Subdomain(x) = %v, want %v", testCase.input, got, want)
+		}
+		if got, want := sub.Depth(), depth+1; got != want {
+			t.Errorf("Parse(%v).Subdomain(x).Depth() = %v, want %v", testCase.input, got, want)
+		}
+	}
+}
+
+func TestFQDN(t *testing.T) {
+	testCases := []validTestCase{
+		{"example.com.", "example.com", &DomainName{TLD: "com", SLD: "example", Rule: MustNewRule("com")}},
+		{"foo.verybritish.co.uk.", "verybritish.co.uk", &DomainName{TLD: "co.uk", SLD: "verybritish", TRD: "foo", Rule: MustNewRule("*.uk")}},
+		{"foo.parliament.uk.", "parliament.uk", &DomainName{TLD: "uk", SLD: "parliament", TRD: "foo", Rule: MustNewRule("!parliament.uk")}},
+		{"bar.foo.blogspot.com.", "foo.blogspot.com", &DomainName{TLD: "blogspot.com", SLD: "foo", TRD: "bar", Rule: MustNewRule("blogspot.com")}},
+	}
+
+	for _, testCase := range testCases {
+		got, err := Parse(testCase.input)
+		if err != nil {
+			t.Errorf("TestFQDN(%v) returned error: %v", testCase.input, err)
+			continue
+		}
+		if want := testCase.parsed; want.String() != got.String() {
+			t.Errorf("TestFQDN(%v) = %v, want %v", testCase.input, got, want)
+		}
+
+		str, err := Domain(testCase.input)
+		if err != nil {
+			t.Errorf("TestFQDN(%v) returned error: %v", testCase.input, err)
+		}
+		if want := testCase.domain; want != str {
+			t.Errorf("TestFQDN(%v) = %v, want %v", testCase.input, str, want)
+		}
+
+		// keep the root dot
+		options := &FindOptions{KeepRootDot: true}
+		got, err = ParseFromListWithOptions(DefaultList, testCase.input, options)
+		if err != nil {
+			t.Errorf("TestFQDN(%v) returned error: %v", testCase.input, err)
+			continue
+		}
+		if want := testCase.parsed.String() + "."; want != got.String() {
+			t.Errorf("TestFQDN(%v) = %v, want %v", testCase.input, got, want)
+		}
+
+		str, _ = DomainFromListWithOptions(DefaultList, testCase.input, options)
+		if want := testCase.domain + "."; want != str {
+			t.Errorf("TestFQDN(%v) = %v, want %v", testCase.input, str, want)
+		}
+	}
+
+	// the root dot is kept only when the name is fully-qualified
+	got, _ := ParseFromListWithOptions(DefaultList, "www.example.com", &FindOptions{KeepRootDot: true})
+	if want := "www.example.com"; want != got.String() {
+		t.Errorf("TestFQDN(%v) = %v, want %v", "www.example.com", got, want)
+	}
+
+	// the root dot alone, and a suffix
+	if _, err := Parse("."); !errors.Is(err, ErrLeadingDot) {
+		t.Errorf("TestFQDN(.) error = %v, want %v", err, ErrLeadingDot)
+	}
+	if _, err := Parse("co.uk."); !errors.Is(err, ErrIsPublicSuffix) {
+		t.Errorf("TestFQDN(co.uk.) error = %v, want %v", err, ErrIsPublicSuffix)
+	}
+
+	// only one root dot is removed
+	for _, input := range []string{"example.com..", "www.example.com..."} {
+		if _, err := Domain(input); !errors.Is(err, ErrEmptyLabel) {
+			t.Errorf("TestFQDN(%v) error = %v, want %v", input, err, ErrEmptyLabel)
+		}
+		if _, err := DomainFromListWithOptions(DefaultList, input, &FindOptions{KeepRootDot: true}); !errors.Is(err, ErrEmptyLabel) {
+			t.Errorf("TestFQDN(%v) with root dot error = %v, want %v", input, err, ErrEmptyLabel)
+		}
+	}
+}
+
 type privateTestCase struct {
 	input  string
 	domain string
@@ -116,23 +248,27 @@ func TestIDNA(t *testing.T) {
 		}
 	}
 
-	// These tests validates the non-acceptance of U-labels.
-	//
-	// TODO(weppos): some tests are passing because of the default rule *
-	// Consider to add some tests overriding the default rule to nil.
-	// Right now, setting the default rule to nil with cause a panic if the lookup results in a nil.
+	// U-labels are converted to A-labels before the lookup,
+	// and the domain is returned in A-labels.
 	testUCases := []idnaTestCase{
-		// U-labels are NOT supported
 		// Check single IDN part
 		{"рф", "", true},
-		{"example.рф", "example.рф", false},           // passes because of *
-		{"subdomain.example.рф", "example.рф", false}, // passes because of *
+		{"example.рф", "example.xn--p1ai", false},
+		{"subdomain.example.рф", "example.xn--p1ai", false},
 		// Check multiple IDN parts
-		{"example-упр.рф", "example-упр.рф", false},           // passes because of *
-		{"subdomain.example-упр.рф", "example-упр.рф", false}, // passes because of *
+		{"example-упр.рф", "xn--example--3bhk5a.xn--p1ai", false},
+		{"subdomain.example-упр.рф", "xn--example--3bhk5a.xn--p1ai", false},
 		// Check multiple IDN rules
-		{"example.упр.срб", "упр.срб", false},
-		{"sudbomain.example.упр.срб", "упр.срб", false},
+		{"example.упр.срб", "example.xn--o1ach.xn--90a3ac", false},
+		{"sudbomain.example.упр.срб", "example.xn--o1ach.xn--90a3ac", false},
+		// Check ideographic full stops
+		{"www.食狮。公司．cn", "xn--85x722f.xn--55qx5d.cn", false},
+		{"www.食狮｡公司.cn", "xn--85x722f.xn--55qx5d.cn", false},
+		{"。公司.cn", "", true},
+		// Check full-width and half-width characters
+		{"Ｅｘａｍｐｌｅ．com", "example.com", false},
+		{"www.ｅｘａｍｐｌｅ.co.uk", "example.co.uk", false},
+		{"ﾃｽﾄ.jp", "xn--zckzah.jp", false},
 	}
 
 	for _, testCase := range testUCases {
@@ -151,6 +287,26 @@ func TestIDNA(t *testing.T) {
 			t.Errorf("U-label Domain(%v) = %v, want %v", testCase.input, got, want)
 		}
 	}
+
+	// The domain is returned in U-labels with the Unicode option.
+	testUnicodeCases := []idnaTestCase{
+		{"example.xn--p1ai", "example.рф", false},
+		{"subdomain.example-упр.рф", "example-упр.рф", false},
+		{"www.食狮。公司．cn", "食狮.公司.cn", false},
+		{"www.example.com", "example.com", false},
+	}
+
+	for _, testCase := range testUnicodeCases {
+		got, err := DomainFromListWithOptions(DefaultList, testCase.input, &FindOptions{Unicode: true})
+		if err != nil {
+			t.Errorf("Unicode %v returned error: %v", testCase.input, err)
+			continue
+		}
+
+		if want := testCase.domain; want != got {
+			t.Errorf("Unicode Domain(%v) = %v, want %v", testCase.input, got, want)
+		}
+	}
 }
 
 func TestFindRuleIANA(t *testing.T) {
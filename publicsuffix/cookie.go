@@ -0,0 +1,72 @@
+package publicsuffix
+
+import (
+	"strings"
+)
+
+// CheckCookieDomain checks whether the host of a request can set a cookie
+// with the Domain attribute using the default (Public Suffix) List.
+// It returns nil if the cookie is allowed, otherwise a *CookieDomainError that says why.
+//
+// Examples:
+//
+//	publicsuffix.CheckCookieDomain("www.example.com", "example.com")
+//	// nil
+//	publicsuffix.CheckCookieDomain("www.example.co.uk", "co.uk")
+//	// error: cookie domain is a public suffix
+//	publicsuffix.CheckCookieDomain("foo.github.io", "github.io")
+//	// error: cookie domain is a public suffix
+func CheckCookieDomain(host, domain string) error {
+	return CheckCookieDomainFromListWithOptions(DefaultList, host, domain, DefaultFindOptions)
+}
+
+// CheckCookieDomainFromListWithOptions is like CheckCookieDomain,
+// but uses the (Public Suffix) list and the options passed as arguments.
+// Set FindOptions.IgnorePrivate to accept cookies for the suffixes in the private section,
+// such as "github.io".
+//
+// The checks follow the storage model of RFC 6265, section 5.3:
+//   - An empty Domain attribute sets a host-only cookie, and it's always allowed.
+//   - A leading dot in the Domain attribute is ignored, a trailing dot is rejected.
+//   - A Domain attribute that is a public suffix is rejected, unless it is identical
+//     to the host: in this case the cookie is host-only.
+//   - The host must domain-match the Domain attribute: they are identical,
+//     or the host ends with a dot followed by the Domain attribute.
+//     An IP address only matches itself.
+//
+// The host and the Domain attribute are compared in lowercase and encoded in ASCII.
+func CheckCookieDomainFromListWithOptions(l *List, host, domain string, options *FindOptions) error {
+	if domain == "" {
+		return nil
+	}
+
+	d := strings.TrimPrefix(domain, ".")
+	if d != "" && d[len(d)-1] == '.' {
+		return &CookieDomainError{Host: host, Domain: domain, Err: ErrCookieDomainMalformed}
+	}
+
+	if ip, ok := parseIP(host); ok {
+		if dip, ok := parseIP(d); !ok || dip != ip {
+			return &CookieDomainError{Host: host, Domain: domain, Err: ErrCookieDomainMismatch}
+		}
+		return nil
+	}
+
+	h, _, err := normalize(host, options)
+	if err != nil {
+		return &CookieDomainError{Host: host, Domain: domain, Err: err}
+	}
+
+	d, _, err = normalize(d, options)
+	if err != nil {
+		return &CookieDomainError{Host: host, Domain: domain, Err: err}
+	}
+
+	if l.IsPublicSuffix(d, options) && d != h {
+		return &CookieDomainError{Host: host, Domain: domain, Err: ErrCookieDomainPublicSuffix}
+	}
+	if h != d && !strings.HasSuffix(h, "."+d) {
+		return &CookieDomainError{Host: host, Domain: domain, Err: ErrCookieDomainMismatch}
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package publicsuffix
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCheckCookieDomain(t *testing.T) {
+	testCases := []struct {
+		host, domain string
+		err          error
+	}{
+		// host-only cookies
+		{"www.example.com", "", nil},
+		{"co.uk", "", nil},
+
+		// domain-match
+		{"example.com", "example.com", nil},
+		{"www.example.com", "example.com", nil},
+		{"www.example.com", ".example.com", nil},
+		{"www.example.com", "www.example.com", nil},
+		{"WWW.Example.COM", "EXAMPLE.com", nil},
+		{"www.example.com.", "example.com", nil},
+		{"www.食狮.公司.cn", "食狮.公司.cn", nil},
+		{"www.食狮.公司.cn", "xn--85x722f.xn--55qx5d.cn", nil},
+		{"www.example.com", "other.com", ErrCookieDomainMismatch},
+		{"www.example.com", "ample.com", ErrCookieDomainMismatch},
+		{"example.com", "www.example.com", ErrCookieDomainMismatch},
+
+		// supercookies
+		{"www.example.com", "com", ErrCookieDomainPublicSuffix},
+		{"www.example.co.uk", "co.uk", ErrCookieDomainPublicSuffix},
+		{"www.example.co.uk", ".co.uk", ErrCookieDomainPublicSuffix},
+		{"foo.github.io", "github.io", ErrCookieDomainPublicSuffix},
+		{"foo.example.test", "test", ErrCookieDomainPublicSuffix},
+		// a public suffix can set a host-only cookie for itself
+		{"github.io", "github.io", nil},
+		{"foo.github.io", "foo.github.io", nil},
+
+		// IP addresses
+		{"192.168.0.1", "192.168.0.1", nil},
+		{"192.168.0.1", "0.1", ErrCookieDomainMismatch},
+		{"192.168.0.1", "168.0.1", ErrCookieDomainMismatch},
+		{"192.168.0.1.", "192.168.0.1", nil},
+		{"192.168.0.1", "192.168.0.1.", ErrCookieDomainMalformed},
+		{"::1", "::1", nil},
+		{"::1", "[::1]", nil},
+
+		// malformed
+		{"www.example.com", "example.com.", ErrCookieDomainMalformed},
+		{"www.example.com", ".", ErrBlankName},
+		{"www.example.com", "..example.com", ErrLeadingDot},
+		{"", "example.com", ErrBlankName},
+	}
+
+	for _, testCase := range testCases {
+		err := CheckCookieDomain(testCase.host, testCase.domain)
+		if !errors.Is(err, testCase.err) {
+			t.Errorf("CheckCookieDomain(%q, %q) = %v, want %v", testCase.host, testCase.domain, err, testCase.err)
+			continue
+		}
+		if err == nil {
+			continue
+		}
+
+		var cerr *CookieDomainError
+		if !errors.As(err, &cerr) || cerr.Host != testCase.host || cerr.Domain != testCase.domain {
+			t.Errorf("CheckCookieDomain(%q, %q) error = %#v, want a *CookieDomainError", testCase.host, testCase.domain, err)
+		}
+	}
+}
+
+func TestCheckCookieDomainFromListWithOptions(t *testing.T) {
+	options := &FindOptions{IgnorePrivate: true, DefaultRule: DefaultRule}
+	if err := CheckCookieDomainFromListWithOptions(DefaultList, "foo.github.io", "github.io", options); err != nil {
+		t.Errorf("CheckCookieDomainFromListWithOptions() = %v, want nil when ignoring private domains", err)
+	}
+	if err := CheckCookieDomainFromListWithOptions(DefaultList, "www.example.co.uk", "co.uk", options); !errors.Is(err, ErrCookieDomainPublicSuffix) {
+		t.Errorf("CheckCookieDomainFromListWithOptions() = %v, want %v", err, ErrCookieDomainPublicSuffix)
+	}
+
+	err := CheckCookieDomain("www.example.co.uk", "co.uk")
+	if want := "cookie domain co.uk for host www.example.co.uk: cookie domain is a public suffix"; err == nil || err.Error() != want {
+		t.Errorf("CheckCookieDomain() error message = %v, want %v", err, want)
+	}
+}
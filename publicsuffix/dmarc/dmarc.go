@@ -0,0 +1,239 @@
+// Package dmarc finds the DMARC organizational domain of a domain name,
+// either with the public suffix list as defined by RFC 7489,
+// or with the DNS tree walk defined by DMARCbis.
+package dmarc
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+
+	"github.com/weppos/publicsuffix-go/publicsuffix"
+)
+
+// maxTreeWalkLabels is the number of labels the tree walk jumps to
+// after the first query, to limit the number of queries to 8.
+const maxTreeWalkLabels = 7
+
+// Method identifies how the organizational domain was found.
+type Method int
+
+const (
+	// MethodPSL means the organizational domain is the registrable domain
+	// according to the public suffix list, as in RFC 7489, section 3.2.
+	MethodPSL Method = iota + 1
+
+	// MethodTreeWalk means the organizational domain was found
+	// with the DMARCbis DNS tree walk.
+	MethodTreeWalk
+)
+
+// String returns the name of the method.
+func (m Method) String() string {
+	switch m {
+	case MethodPSL:
+		return "psl"
+	case MethodTreeWalk:
+		return "tree-walk"
+	default:
+		return "unknown"
+	}
+}
+
+// Resolver looks up the TXT records of a name.
+//
+// A *net.Resolver satisfies this interface. A name without records
+// should return a *net.DNSError with IsNotFound set, or no records.
+type Resolver interface {
+	LookupTXT(ctx context.Context, name string) ([]string, error)
+}
+
+// Result is the organizational domain of a domain, and how it was found.
+type Result struct {
+	// Domain is the organizational domain.
+	Domain string
+
+	// Method is the method that found the organizational domain.
+	Method Method
+
+	// Record is the DMARC record that determined the organizational domain,
+	// when the Method is MethodTreeWalk.
+	Record string
+}
+
+// OrganizationalDomain returns the organizational domain of the domain
+// as defined by RFC 7489, section 3.2, using the default (Public Suffix) List.
+//
+// Examples:
+//
+//	dmarc.OrganizationalDomain("mail.example.co.uk")
+//	// example.co.uk
+func OrganizationalDomain(domain string) (string, error) {
+	return OrganizationalDomainFromListWithOptions(publicsuffix.DefaultList, domain, publicsuffix.DefaultFindOptions)
+}
+
+// OrganizationalDomainFromListWithOptions is like OrganizationalDomain,
+// but uses the (Public Suffix) list and the options passed as arguments.
+//
+// The organizational domain is the registrable domain returned by
+// publicsuffix.ParseFromListWithOptions, hence it fails if the domain is a public suffix.
+func OrganizationalDomainFromListWithOptions(l *publicsuffix.List, domain string, options *publicsuffix.FindOptions) (string, error) {
+	dn, err := publicsuffix.ParseFromListWithOptions(l, domain, options)
+	if err != nil {
+		return "", err
+	}
+	return dn.SLD + "." + dn.TLD, nil
+}
+
+// Finder finds the organizational domain with the DMARCbis DNS tree walk,
+// and falls back to the public suffix list when the walk finds no DMARC record.
+type Finder struct {
+	// Resolver looks up the DMARC records.
+	// If nil, the tree walk is skipped and only the public suffix list is used.
+	Resolver Resolver
+
+	// List is the (Public Suffix) list used as a fallback.
+	// If nil, the publicsuffix.DefaultList is used.
+	List *publicsuffix.List
+
+	// FindOptions are the options used to search the List.
+	// If nil, the publicsuffix.DefaultFindOptions are used.
+	FindOptions *publicsuffix.FindOptions
+}
+
+// OrganizationalDomain returns the organizational domain of the domain,
+// and reports which method found it.
+//
+// The tree walk queries the _dmarc TXT record of the domain and of its parents,
+// jumping to the parent with 7 labels after the first query, and stopping at the TLD.
+// A record with psd=n marks the organizational domain, a record with psd=y marks
+// a public suffix domain, hence the organizational domain is the name one label below.
+// Otherwise, the organizational domain is the name with the fewest labels that has a record.
+//
+// A resolver error, other than a name not found, is returned as it is.
+func (f *Finder) OrganizationalDomain(ctx context.Context, domain string) (*Result, error) {
+	if f.Resolver != nil {
+		name, err := publicsuffix.ToASCII(strings.TrimSuffix(strings.ToLower(domain), "."))
+		if err != nil {
+			return nil, err
+		}
+
+		res, err := treeWalk(ctx, f.Resolver, name)
+		if err != nil || res != nil {
+			return res, err
+		}
+	}
+
+	l, options := f.List, f.FindOptions
+	if l == nil {
+		l = publicsuffix.DefaultList
+	}
+	if options == nil {
+		options = publicsuffix.DefaultFindOptions
+	}
+
+	org, err := OrganizationalDomainFromListWithOptions(l, domain, options)
+	if err != nil {
+		return nil, err
+	}
+	return &Result{Domain: org, Method: MethodPSL}, nil
+}
+
+// treeWalk performs the DMARCbis DNS tree walk.
+// It returns nil if no DMARC record is found.
+func treeWalk(ctx context.Context, r Resolver, domain string) (*Result, error) {
+	var found *Result
+
+	for name := domain; name != ""; name = parent(name) {
+		record, err := lookupRecord(ctx, r, name)
+		if err != nil {
+			return nil, err
+		}
+
+		if record != "" {
+			switch psd(record) {
+			case "n":
+				return &Result{Domain: name, Method: MethodTreeWalk, Record: record}, nil
+			case "y":
+				return &Result{Domain: below(domain, name), Method: MethodTreeWalk, Record: record}, nil
+			default:
+				found = &Result{Domain: name, Method: MethodTreeWalk, Record: record}
+			}
+		}
+	}
+	return found, nil
+}
+
+// below returns the name one label below name on the way to domain,
+// such as "example.gov" for "gov" in "a.mail.example.gov".
+// It returns name itself when name is the domain.
+//
+// The label is taken from domain rather than from the previous name in the walk,
+// which is more than one label below after the jump of parent.
+func below(domain, name string) string {
+	if name == domain {
+		return name
+	}
+	rest := strings.TrimSuffix(domain, "."+name)
+	if i := strings.LastIndexByte(rest, '.'); i >= 0 {
+		rest = rest[i+1:]
+	}
+	return rest + "." + name
+}
+
+// lookupRecord returns the DMARC record of the name, or an empty string if there is none.
+// A name with more than one DMARC record has no valid record.
+func lookupRecord(ctx context.Context, r Resolver, name string) (string, error) {
+	txts, err := r.LookupTXT(ctx, "_dmarc."+name)
+	if err != nil {
+		var derr *net.DNSError
+		if errors.As(err, &derr) && derr.IsNotFound {
+			return "", nil
+		}
+		return "", err
+	}
+
+	var record string
+	for _, txt := range txts {
+		if !isRecord(txt) {
+			continue
+		}
+		if record != "" {
+			return "", nil
+		}
+		record = txt
+	}
+	return record, nil
+}
+
+// isRecord reports whether the TXT record is a DMARC record.
+func isRecord(txt string) bool {
+	version, _, _ := strings.Cut(txt, ";")
+	return strings.TrimSpace(version) == "v=DMARC1"
+}
+
+// psd returns the value of the psd tag of the DMARC record, or an empty string.
+func psd(record string) string {
+	for _, tag := range strings.Split(record, ";") {
+		name, value, ok := strings.Cut(tag, "=")
+		if ok && strings.TrimSpace(name) == "psd" {
+			return strings.TrimSpace(value)
+		}
+	}
+	return ""
+}
+
+// parent returns the next name to query in the tree walk,
+// or an empty string when the name is a TLD.
+func parent(name string) string {
+	labels := strings.Split(name, ".")
+	switch {
+	case len(labels) <= 1:
+		return ""
+	case len(labels) > maxTreeWalkLabels+1:
+		return strings.Join(labels[len(labels)-maxTreeWalkLabels:], ".")
+	default:
+		return strings.Join(labels[1:], ".")
+	}
+}
@@ -0,0 +1,200 @@
+package dmarc
+
+import (
+	"context"
+	"errors"
+	"net"
+	"reflect"
+	"testing"
+
+	"github.com/weppos/publicsuffix-go/publicsuffix"
+)
+
+// fakeResolver resolves the TXT records from a map, and records the queries.
+type fakeResolver struct {
+	records map[string][]string
+	errors  map[string]error
+	queries []string
+}
+
+func (r *fakeResolver) LookupTXT(_ context.Context, name string) ([]string, error) {
+	r.queries = append(r.queries, name)
+	if err, ok := r.errors[name]; ok {
+		return nil, err
+	}
+	if txts, ok := r.records[name]; ok {
+		return txts, nil
+	}
+	return nil, &net.DNSError{Err: "no such host", Name: name, IsNotFound: true}
+}
+
+func TestOrganizationalDomain(t *testing.T) {
+	testCases := map[string]string{
+		"example.com":               "example.com",
+		"mail.example.com":          "example.com",
+		"a.b.mail.example.co.uk":    "example.co.uk",
+		"mail.example.blogspot.com": "example.blogspot.com",
+		"MAIL.Example.COM.":         "example.com",
+	}
+
+	for input, want := range testCases {
+		got, err := OrganizationalDomain(input)
+		if err != nil {
+			t.Errorf("OrganizationalDomain(%v) returned error: %v", input, err)
+			continue
+		}
+		if got != want {
+			t.Errorf("OrganizationalDomain(%v) = %v, want %v", input, got, want)
+		}
+	}
+
+	if _, err := OrganizationalDomain("co.uk"); !errors.Is(err, publicsuffix.ErrIsPublicSuffix) {
+		t.Errorf("OrganizationalDomain(co.uk) error = %v, want %v", err, publicsuffix.ErrIsPublicSuffix)
+	}
+}
+
+func TestFinder_TreeWalk(t *testing.T) {
+	testCases := []struct {
+		name    string
+		domain  string
+		records map[string][]string
+		want    *Result
+	}{
+		{
+			name:   "record at the organizational domain",
+			domain: "mail.example.com",
+			records: map[string][]string{
+				"_dmarc.example.com": {"v=DMARC1; p=reject"},
+			},
+			want: &Result{Domain: "example.com", Method: MethodTreeWalk, Record: "v=DMARC1; p=reject"},
+		},
+		{
+			name:   "the record with the fewest labels wins",
+			domain: "a.mail.example.com",
+			records: map[string][]string{
+				"_dmarc.mail.example.com": {"v=DMARC1; p=none"},
+				"_dmarc.example.com":      {"v=DMARC1; p=reject"},
+			},
+			want: &Result{Domain: "example.com", Method: MethodTreeWalk, Record: "v=DMARC1; p=reject"},
+		},
+		{
+			name:   "psd=n marks the organizational domain",
+			domain: "a.mail.example.com",
+			records: map[string][]string{
+				"_dmarc.mail.example.com": {"v=DMARC1; p=none; psd=n"},
+				"_dmarc.example.com":      {"v=DMARC1; p=reject"},
+			},
+			want: &Result{Domain: "mail.example.com", Method: MethodTreeWalk, Record: "v=DMARC1; p=none; psd=n"},
+		},
+		{
+			name:   "psd=y marks a public suffix domain",
+			domain: "a.mail.example.gov",
+			records: map[string][]string{
+				"_dmarc.gov": {"v=DMARC1; p=reject; psd=y"},
+			},
+			want: &Result{Domain: "example.gov", Method: MethodTreeWalk, Record: "v=DMARC1; p=reject; psd=y"},
+		},
+		{
+			name:   "psd=y after the walk skips labels",
+			domain: "a.b.c.d.e.f.g.h.i.example.gov",
+			records: map[string][]string{
+				"_dmarc.e.f.g.h.i.example.gov": {"v=DMARC1; p=reject; psd=y"},
+			},
+			want: &Result{Domain: "d.e.f.g.h.i.example.gov", Method: MethodTreeWalk, Record: "v=DMARC1; p=reject; psd=y"},
+		},
+		{
+			name:   "records that are not DMARC are ignored",
+			domain: "mail.example.com",
+			records: map[string][]string{
+				"_dmarc.mail.example.com": {"v=spf1 -all"},
+				"_dmarc.example.com":      {"v=DMARC1; p=reject", "google-site-verification=abc"},
+			},
+			want: &Result{Domain: "example.com", Method: MethodTreeWalk, Record: "v=DMARC1; p=reject"},
+		},
+		{
+			name:   "multiple DMARC records are not valid",
+			domain: "mail.example.co.uk",
+			records: map[string][]string{
+				"_dmarc.example.co.uk": {"v=DMARC1; p=reject", "v=DMARC1; p=none"},
+			},
+			want: &Result{Domain: "example.co.uk", Method: MethodPSL},
+		},
+		{
+			name:    "no records falls back to the public suffix list",
+			domain:  "mail.example.co.uk",
+			records: map[string][]string{},
+			want:    &Result{Domain: "example.co.uk", Method: MethodPSL},
+		},
+	}
+
+	for _, testCase := range testCases {
+		f := &Finder{Resolver: &fakeResolver{records: testCase.records}}
+		got, err := f.OrganizationalDomain(context.Background(), testCase.domain)
+		if err != nil {
+			t.Errorf("%s: OrganizationalDomain(%v) returned error: %v", testCase.name, testCase.domain, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, testCase.want) {
+			t.Errorf("%s: OrganizationalDomain(%v) = %+v, want %+v", testCase.name, testCase.domain, got, testCase.want)
+		}
+	}
+}
+
+func TestFinder_TreeWalkQueries(t *testing.T) {
+	r := &fakeResolver{}
+	f := &Finder{Resolver: r}
+	if _, err := f.OrganizationalDomain(context.Background(), "a.b.c.d.e.f.g.h.i.example.com"); err != nil {
+		t.Fatalf("OrganizationalDomain() returned error: %v", err)
+	}
+
+	want := []string{
+		"_dmarc.a.b.c.d.e.f.g.h.i.example.com",
+		"_dmarc.e.f.g.h.i.example.com",
+		"_dmarc.f.g.h.i.example.com",
+		"_dmarc.g.h.i.example.com",
+		"_dmarc.h.i.example.com",
+		"_dmarc.i.example.com",
+		"_dmarc.example.com",
+		"_dmarc.com",
+	}
+	if !reflect.DeepEqual(r.queries, want) {
+		t.Errorf("OrganizationalDomain() queries = %v, want %v", r.queries, want)
+	}
+}
+
+func TestFinder_ResolverError(t *testing.T) {
+	errTimeout := &net.DNSError{Err: "i/o timeout", Name: "_dmarc.example.com", IsTimeout: true}
+	f := &Finder{Resolver: &fakeResolver{errors: map[string]error{"_dmarc.example.com": errTimeout}}}
+
+	if _, err := f.OrganizationalDomain(context.Background(), "mail.example.com"); !errors.Is(err, errTimeout) {
+		t.Errorf("OrganizationalDomain() error = %v, want %v", err, errTimeout)
+	}
+}
+
+func TestFinder_WithoutResolver(t *testing.T) {
+	list := publicsuffix.NewList()
+	_ = list.AddRule(publicsuffix.MustNewRule("example"))
+	f := &Finder{List: list}
+
+	got, err := f.OrganizationalDomain(context.Background(), "mail.foo.example")
+	if err != nil {
+		t.Fatalf("OrganizationalDomain() returned error: %v", err)
+	}
+	if want := (&Result{Domain: "foo.example", Method: MethodPSL}); !reflect.DeepEqual(got, want) {
+		t.Errorf("OrganizationalDomain() = %+v, want %+v", got, want)
+	}
+}
+
+func TestMethod_String(t *testing.T) {
+	testCases := map[Method]string{
+		MethodPSL:      "psl",
+		MethodTreeWalk: "tree-walk",
+		Method(0):      "unknown",
+	}
+
+	for method, want := range testCases {
+		if got := method.String(); got != want {
+			t.Errorf("Method(%d).String() = %v, want %v", method, got, want)
+		}
+	}
+}
@@ -0,0 +1,219 @@
+package publicsuffix
+
+import (
+	"iter"
+	"strings"
+)
+
+// Ancestors returns an iterator over the domain name and its parent domains,
+// from the full name up to the registrable domain.
+// The domains are derived from TLD, SLD and TRD, hence the list is not searched again.
+//
+// An IP address yields only the address, and an empty DomainName yields nothing.
+//
+// Examples:
+//
+//	dn, _ := publicsuffix.Parse("a.b.example.co.uk")
+//	for domain := range dn.Ancestors() {
+//		fmt.Println(domain)
+//	}
+//	// a.b.example.co.uk
+//	// b.example.co.uk
+//	// example.co.uk
+func (d *DomainName) Ancestors() iter.Seq[string] {
+	return d.ancestors(false)
+}
+
+// AncestorsWithSuffix is like Ancestors, but the iterator goes on
+// through the labels of the public suffix, up to the top-level label.
+//
+// Examples:
+//
+//	dn, _ := publicsuffix.Parse("www.example.co.uk")
+//	for domain := range dn.AncestorsWithSuffix() {
+//		fmt.Println(domain)
+//	}
+//	// www.example.co.uk
+//	// example.co.uk
+//	// co.uk
+//	// uk
+func (d *DomainName) AncestorsWithSuffix() iter.Seq[string] {
+	return d.ancestors(true)
+}
+
+func (d *DomainName) ancestors(suffix bool) iter.Seq[string] {
+	return func(yield func(string) bool) {
+		if d.IP.IsValid() {
+			yield(d.IP.String())
+			return
+		}
+
+		name, dot := d.String(), ""
+		if name == "" {
+			return
+		}
+		if d.RootDot {
+			name, dot = name[:len(name)-1], "."
+		}
+
+		// the last domain of the chain
+		last := len(d.SLD) + 1 + len(d.TLD)
+		if d.SLD == "" || suffix {
+			last = len(name) - strings.LastIndexByte(name, '.') - 1
+		}
+
+		for {
+			if !yield(name + dot) {
+				return
+			}
+			if len(name) <= last {
+				return
+			}
+			name = name[strings.IndexByte(name, '.')+1:]
+		}
+	}
+}
+
+// Labels returns the labels of the domain name, from left to right.
+// It allocates only the slice: the labels are substrings of TRD, SLD and TLD.
+//
+// Examples:
+//
+//	dn, _ := publicsuffix.Parse("www.example.co.uk")
+//	dn.Labels()
+//	// ["www", "example", "co", "uk"]
+func (d *DomainName) Labels() []string {
+	if d.TLD == "" {
+		return nil
+	}
+
+	n := strings.Count(d.TLD, ".") + 1
+	if d.SLD != "" {
+		n++
+	}
+	if d.TRD != "" {
+		n += d.Depth()
+	}
+
+	labels := make([]string, 0, n)
+	for _, part := range [3]string{d.TRD, d.SLD, d.TLD} {
+		for part != "" {
+			label, rest, _ := strings.Cut(part, ".")
+			labels = append(labels, label)
+			part = rest
+		}
+	}
+	return labels
+}
+
+// Depth returns the number of labels of the subdomain (TRD),
+// that is 0 for a registrable domain such as "example.co.uk",
+// and 2 for "a.b.example.co.uk".
+func (d *DomainName) Depth() int {
+	if d.TRD == "" {
+		return 0
+	}
+	return strings.Count(d.TRD, ".") + 1
+}
+
+// Registrable returns the registrable domain, that is the SLD and the TLD,
+// such as "example.co.uk" for "www.example.co.uk".
+// It returns an empty string when there is no SLD.
+func (d *DomainName) Registrable() string {
+	if d.SLD == "" || d.TLD == "" {
+		return ""
+	}
+	if d.RootDot {
+		return d.SLD + "." + d.TLD + "."
+	}
+	return d.SLD + "." + d.TLD
+}
+
+// Suffix returns the public suffix, that is the TLD, such as "co.uk" for "www.example.co.uk".
+func (d *DomainName) Suffix() string {
+	if d.RootDot && d.TLD != "" {
+		return d.TLD + "."
+	}
+	return d.TLD
+}
+
+// ICANN reports whether the public suffix is in the ICANN section of the list.
+// It returns false for a suffix in the private section, or when the default rule "*" applies.
+func (d *DomainName) ICANN() bool {
+	return d.Rule != nil && d.Rule.isICANN()
+}
+
+// Private reports whether the public suffix is in the private section of the list,
+// such as "blogspot.com".
+func (d *DomainName) Private() bool {
+	return d.Rule != nil && d.Rule.Private
+}
+
+// Parent returns the parent of a subdomain, removing the left-most label of the TRD,
+// or nil if the domain name has no TRD.
+//
+// Examples:
+//
+//	dn, _ := publicsuffix.Parse("a.b.example.co.uk")
+//	dn.Parent()
+//	// &DomainName{"co.uk", "example", "b"}
+//	dn.Parent().Parent()
+//	// &DomainName{"co.uk", "example", ""}
+//	dn.Parent().Parent().Parent()
+//	// nil
+func (d *DomainName) Parent() *DomainName {
+	if d.TRD == "" {
+		return nil
+	}
+
+	p := *d
+	if _, rest, ok := strings.Cut(d.TRD, "."); ok {
+		p.TRD = rest
+	} else {
+		p.TRD = ""
+	}
+	return &p
+}
+
+// Subdomain returns a new domain name with the labels prepended to the domain name.
+// The labels are normalized as in ParseFromListWithOptions: they are converted
+// to lowercase and to ASCII, and they must not be blank or start with a dot.
+// Empty labels, as in "a..b" or "www.", fail with ErrEmptyLabel.
+//
+// Examples:
+//
+//	dn, _ := publicsuffix.Parse("example.co.uk")
+//	dn.Subdomain("www")
+//	// &DomainName{"co.uk", "example", "www"}
+//	dn.Subdomain("a.b")
+//	// &DomainName{"co.uk", "example", "a.b"}
+func (d *DomainName) Subdomain(labels string) (*DomainName, error) {
+	if d.IP.IsValid() {
+		return nil, &ParseError{Name: labels, Err: ErrIPAddress}
+	}
+
+	n, fqdn, err := normalize(labels, nil)
+	if err != nil {
+		return nil, err
+	}
+	// the root dot is an empty label too, since the domain name follows the labels
+	if fqdn || strings.Contains(n, "..") {
+		return nil, &ParseError{Name: labels, Err: ErrEmptyLabel}
+	}
+
+	s := *d
+	switch {
+	case d.SLD == "":
+		// a public suffix, the right-most label becomes the SLD
+		if i := strings.LastIndexByte(n, '.'); i < 0 {
+			s.SLD = n
+		} else {
+			s.TRD, s.SLD = n[:i], n[i+1:]
+		}
+	case d.TRD == "":
+		s.TRD = n
+	default:
+		s.TRD = n + "." + d.TRD
+	}
+	return &s, nil
+}
@@ -0,0 +1,190 @@
+package publicsuffix
+
+import (
+	"errors"
+	"net/netip"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func TestDomainNameAncestors(t *testing.T) {
+	testCases := []struct {
+		input      string
+		ancestors  []string
+		withSuffix []string
+	}{
+		{
+			"example.com",
+			[]string{"example.com"},
+			[]string{"example.com", "com"},
+		},
+		{
+			"a.b.example.co.uk",
+			[]string{"a.b.example.co.uk", "b.example.co.uk", "example.co.uk"},
+			[]string{"a.b.example.co.uk", "b.example.co.uk", "example.co.uk", "co.uk", "uk"},
+		},
+		{
+			"www.foo.blogspot.com",
+			[]string{"www.foo.blogspot.com", "foo.blogspot.com"},
+			[]string{"www.foo.blogspot.com", "foo.blogspot.com", "blogspot.com", "com"},
+		},
+		{
+			"www.city.kobe.jp",
+			[]string{"www.city.kobe.jp", "city.kobe.jp"},
+			[]string{"www.city.kobe.jp", "city.kobe.jp", "kobe.jp", "jp"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		dn, err := Parse(testCase.input)
+		if err != nil {
+			t.Fatalf("Parse(%v) returned error: %v", testCase.input, err)
+		}
+
+		if got := slices.Collect(dn.Ancestors()); !reflect.DeepEqual(got, testCase.ancestors) {
+			t.Errorf("Parse(%v).Ancestors() = %v, want %v", testCase.input, got, testCase.ancestors)
+		}
+		if got := slices.Collect(dn.AncestorsWithSuffix()); !reflect.DeepEqual(got, testCase.withSuffix) {
+			t.Errorf("Parse(%v).AncestorsWithSuffix() = %v, want %v", testCase.input, got, testCase.withSuffix)
+		}
+	}
+}
+
+func TestDomainNameAncestors_Special(t *testing.T) {
+	dn, _ := ParseFromListWithOptions(DefaultList, "www.example.co.uk.", &FindOptions{KeepRootDot: true})
+	if got, want := slices.Collect(dn.Ancestors()), []string{"www.example.co.uk.", "example.co.uk."}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Ancestors() with root dot = %v, want %v", got, want)
+	}
+
+	dn = &DomainName{IP: netip.MustParseAddr("192.168.0.1")}
+	if got, want := slices.Collect(dn.AncestorsWithSuffix()), []string{"192.168.0.1"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Ancestors() of an IP = %v, want %v", got, want)
+	}
+
+	dn = &DomainName{TLD: "com"}
+	if got, want := slices.Collect(dn.Ancestors()), []string{"com"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Ancestors() of a suffix = %v, want %v", got, want)
+	}
+
+	dn = &DomainName{}
+	if got := slices.Collect(dn.AncestorsWithSuffix()); got != nil {
+		t.Errorf("Ancestors() of an empty DomainName = %v, want nil", got)
+	}
+
+	// stop early
+	dn, _ = Parse("a.b.c.example.com")
+	for domain := range dn.AncestorsWithSuffix() {
+		if domain != "a.b.c.example.com" {
+			t.Errorf("Ancestors() yielded %v after the break", domain)
+		}
+		break
+	}
+}
+
+func TestDomainNameMethods_Special(t *testing.T) {
+	dn, _ := ParseFromListWithOptions(DefaultList, "www.example.co.uk.", &FindOptions{KeepRootDot: true})
+	if got, want := dn.Registrable(), "example.co.uk."; got != want {
+		t.Errorf("Registrable() with root dot = %v, want %v", got, want)
+	}
+	if got, want := dn.Suffix(), "co.uk."; got != want {
+		t.Errorf("Suffix() with root dot = %v, want %v", got, want)
+	}
+	if got, want := dn.Labels(), []string{"www", "example", "co", "uk"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Labels() with root dot = %v, want %v", got, want)
+	}
+
+	// the default rule is not in the ICANN section
+	dn, _ = Parse("www.example.test")
+	if dn.ICANN() || dn.Private() {
+		t.Errorf("Parse(www.example.test) ICANN() = %v, Private() = %v, want false, false", dn.ICANN(), dn.Private())
+	}
+
+	dn = &DomainName{}
+	if got := dn.Labels(); got != nil {
+		t.Errorf("Labels() of an empty DomainName = %v, want nil", got)
+	}
+	if got := dn.Registrable(); got != "" {
+		t.Errorf("Registrable() of an empty DomainName = %v, want empty", got)
+	}
+	if dn.ICANN() || dn.Private() {
+		t.Errorf("ICANN() and Private() of an empty DomainName should be false")
+	}
+}
+
+func TestDomainNameSubdomain(t *testing.T) {
+	testCases := []struct {
+		dn     *DomainName
+		labels string
+		want   *DomainName
+	}{
+		{
+			&DomainName{TLD: "co.uk", SLD: "example"},
+			"WWW",
+			&DomainName{TLD: "co.uk", SLD: "example", TRD: "www"},
+		},
+		{
+			&DomainName{TLD: "co.uk", SLD: "example", TRD: "b"},
+			"a",
+			&DomainName{TLD: "co.uk", SLD: "example", TRD: "a.b"},
+		},
+		{
+			&DomainName{TLD: "co.uk", SLD: "example"},
+			"a.b",
+			&DomainName{TLD: "co.uk", SLD: "example", TRD: "a.b"},
+		},
+		{
+			&DomainName{TLD: "co.uk"},
+			"a.example",
+			&DomainName{TLD: "co.uk", SLD: "example", TRD: "a"},
+		},
+		{
+			&DomainName{TLD: "com", SLD: "example"},
+			"食狮",
+			&DomainName{TLD: "com", SLD: "example", TRD: "xn--85x722f"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		got, err := testCase.dn.Subdomain(testCase.labels)
+		if err != nil {
+			t.Errorf("%v.Subdomain(%v) returned error: %v", testCase.dn, testCase.labels, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, testCase.want) {
+			t.Errorf("%v.Subdomain(%v) = %+v, want %+v", testCase.dn, testCase.labels, got, testCase.want)
+		}
+	}
+
+	dn := &DomainName{TLD: "com", SLD: "example"}
+	if _, err := dn.Subdomain(""); !errors.Is(err, ErrBlankName) {
+		t.Errorf("Subdomain() error = %v, want %v", err, ErrBlankName)
+	}
+	if _, err := dn.Subdomain(".www"); !errors.Is(err, ErrLeadingDot) {
+		t.Errorf("Subdomain(.www) error = %v, want %v", err, ErrLeadingDot)
+	}
+	for _, labels := range []string{"a..b", "www.", "www.."} {
+		if _, err := dn.Subdomain(labels); !errors.Is(err, ErrEmptyLabel) {
+			t.Errorf("Subdomain(%v) error = %v, want %v", labels, err, ErrEmptyLabel)
+		}
+	}
+
+	dn = &DomainName{IP: netip.MustParseAddr("192.168.0.1")}
+	if _, err := dn.Subdomain("www"); !errors.Is(err, ErrIPAddress) {
+		t.Errorf("Subdomain(www) of an IP error = %v, want %v", err, ErrIPAddress)
+	}
+}
+
+func TestDomainNameMethods_Allocs(t *testing.T) {
+	dn, _ := Parse("a.b.example.co.uk")
+
+	if n := testing.AllocsPerRun(100, func() { _ = dn.Depth(); _ = dn.Suffix(); _ = dn.ICANN(); _ = dn.Private() }); n != 0 {
+		t.Errorf("Depth(), Suffix(), ICANN() and Private() allocated %v times, want 0", n)
+	}
+	if n := testing.AllocsPerRun(100, func() { _ = dn.Labels() }); n > 1 {
+		t.Errorf("Labels() allocated %v times, want at most 1", n)
+	}
+	if n := testing.AllocsPerRun(100, func() { _ = dn.Registrable() }); n > 1 {
+		t.Errorf("Registrable() allocated %v times, want at most 1", n)
+	}
+}
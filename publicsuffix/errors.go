@@ -0,0 +1,237 @@
+package publicsuffix
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrBlankName is returned when the name to parse is blank.
+	ErrBlankName = errors.New("name is blank")
+
+	// ErrLeadingDot is returned when the name to parse starts with a dot.
+	ErrLeadingDot = errors.New("name starts with a dot")
+
+	// ErrInvalidIDN is returned when the name to parse is encoded in Unicode
+	// and it cannot be converted to ASCII.
+	ErrInvalidIDN = errors.New("name is not a valid internationalized domain name")
+
+	// ErrIPAddress is returned when the name to parse is an IP address literal,
+	// unless the FindOptions allow it.
+	ErrIPAddress = errors.New("name is an IP address")
+
+	// ErrNoAtSign is returned when the email address to parse has no "@".
+	ErrNoAtSign = errors.New("email address has no @")
+
+	// ErrInvalidPort is returned when the port of the host:port to parse is not a number
+	// between 0 and 65535.
+	ErrInvalidPort = errors.New("invalid port")
+
+	// ErrIsPublicSuffix is returned when the name to parse is itself a public suffix,
+	// hence there is no registrable domain.
+	ErrIsPublicSuffix = errors.New("name is a public suffix")
+
+	// ErrNoRuleMatch is returned when no rule matches the name to parse,
+	// and the FindOptions don't provide a DefaultRule.
+	ErrNoRuleMatch = errors.New("no rule matching name")
+)
+
+// ParseError is the error returned when a name cannot be parsed.
+//
+// Use errors.Is to check the reason of the failure against one of the Err* sentinel errors,
+// and errors.As to access the name and the rule.
+type ParseError struct {
+	// Name is the name that failed to parse, as it was passed to the function,
+	// before it is lowercased and converted to ASCII.
+	Name string
+
+	// Rule is the rule that matched the name, if any.
+	Rule *Rule
+
+	// Err is the reason of the failure.
+	Err error
+}
+
+// Error implements error.
+func (e *ParseError) Error() string {
+	switch e.Err {
+	case ErrBlankName:
+		return "name is blank"
+	case ErrLeadingDot:
+		return fmt.Sprintf("name %s starts with a dot", e.Name)
+	case ErrIsPublicSuffix:
+		return fmt.Sprintf("%s is a suffix", e.Name)
+	case ErrNoRuleMatch:
+		return fmt.Sprintf("no rule matching name %s", e.Name)
+	default:
+		return fmt.Sprintf("name %s: %v", e.Name, e.Err)
+	}
+}
+
+// Unwrap returns the reason of the failure.
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+var (
+	// ErrDuplicateRule is reported in strict mode when a rule appears more than once in the source.
+	ErrDuplicateRule = errors.New("duplicate rule")
+
+	// ErrEmptyLabel is reported in strict mode when a rule contains an empty label,
+	// by the hostname validation when a name contains an empty label,
+	// and when the name to parse ends with more than one dot, as in "example.com..".
+	ErrEmptyLabel = errors.New("empty label")
+
+	// ErrMisplacedWildcard is reported in strict mode when a rule contains a "*"
+	// anywhere but as the left-most label.
+	ErrMisplacedWildcard = errors.New("misplaced wildcard")
+
+	// ErrOrphanException is reported in strict mode when an exception rule
+	// has no wildcard rule it is an exception to.
+	ErrOrphanException = errors.New("exception rule without a matching wildcard rule")
+)
+
+// LineError is the error returned when a line of a Public Suffix source cannot be loaded.
+//
+// In strict mode the parser returns all the LineErrors joined with errors.Join.
+type LineError struct {
+	// Line is the 1-based number of the line in the source.
+	Line int
+
+	// Text is the content of the line.
+	Text string
+
+	// Err is the reason of the failure.
+	Err error
+}
+
+// Error implements error.
+func (e *LineError) Error() string {
+	return fmt.Sprintf("line %d: %q: %v", e.Line, e.Text, e.Err)
+}
+
+// Unwrap returns the reason of the failure.
+func (e *LineError) Unwrap() error {
+	return e.Err
+}
+
+var (
+	// ErrNameTooLong is reported when the name is longer than 253 octets.
+	ErrNameTooLong = errors.New("name is longer than 253 octets")
+
+	// ErrLabelTooLong is reported when a label of the name is longer than 63 octets.
+	ErrLabelTooLong = errors.New("label is longer than 63 octets")
+
+	// ErrInvalidCharacter is reported when a label of the name contains a character
+	// that is not allowed by the HostnameValidation, such as a space or an underscore.
+	ErrInvalidCharacter = errors.New("invalid character")
+
+	// ErrInvalidHyphen is reported when a label of the name starts or ends with a hyphen.
+	ErrInvalidHyphen = errors.New("label starts or ends with a hyphen")
+
+	// ErrHasPort is reported when the name ends with a port, such as "example.com:443".
+	ErrHasPort = errors.New("name has a port")
+)
+
+// HostnameError is the error wrapped by a *ParseError when a name fails the hostname validation.
+//
+// Use errors.Is to check the reason of the failure against one of ErrEmptyLabel, ErrNameTooLong,
+// ErrLabelTooLong, ErrInvalidCharacter, ErrInvalidHyphen or ErrHasPort.
+type HostnameError struct {
+	// Label is the label that failed the validation,
+	// empty when the problem concerns the entire name.
+	Label string
+
+	// Offset is the 0-based position in the name, in octets, where the problem was found.
+	Offset int
+
+	// Err is the reason of the failure.
+	Err error
+}
+
+// Error implements error.
+func (e *HostnameError) Error() string {
+	if e.Label == "" {
+		return fmt.Sprintf("%v at offset %d", e.Err, e.Offset)
+	}
+	return fmt.Sprintf("label %q at offset %d: %v", e.Label, e.Offset, e.Err)
+}
+
+// Unwrap returns the reason of the failure.
+func (e *HostnameError) Unwrap() error {
+	return e.Err
+}
+
+var (
+	// ErrCookieDomainPublicSuffix is reported when the Domain attribute of a cookie is a public suffix,
+	// and the cookie would be shared by every domain under the suffix.
+	ErrCookieDomainPublicSuffix = errors.New("cookie domain is a public suffix")
+
+	// ErrCookieDomainMismatch is reported when the request host doesn't domain-match
+	// the Domain attribute of a cookie.
+	ErrCookieDomainMismatch = errors.New("host does not match the cookie domain")
+
+	// ErrCookieDomainMalformed is reported when the Domain attribute of a cookie ends with a dot.
+	ErrCookieDomainMalformed = errors.New("malformed cookie domain")
+)
+
+// CookieDomainError is the error returned when a host cannot set a cookie with a Domain attribute.
+//
+// Use errors.Is to check the reason of the failure against one of ErrCookieDomainPublicSuffix,
+// ErrCookieDomainMismatch or ErrCookieDomainMalformed. When the host or the Domain attribute
+// cannot be parsed, the reason is a *ParseError.
+type CookieDomainError struct {
+	// Host is the host of the request.
+	Host string
+
+	// Domain is the Domain attribute of the cookie.
+	Domain string
+
+	// Err is the reason of the failure.
+	Err error
+}
+
+// Error implements error.
+func (e *CookieDomainError) Error() string {
+	return fmt.Sprintf("cookie domain %s for host %s: %v", e.Domain, e.Host, e.Err)
+}
+
+// Unwrap returns the reason of the failure.
+func (e *CookieDomainError) Unwrap() error {
+	return e.Err
+}
+
+var (
+	// ErrWildcardPublicSuffix is reported when a wildcard covers a public suffix, as in "*.co.uk".
+	ErrWildcardPublicSuffix = errors.New("wildcard covers a public suffix")
+
+	// ErrInvalidWildcard is reported when a wildcard is not the entire left-most label of a name,
+	// as in "foo.*.example.com" or "f*.example.com".
+	ErrInvalidWildcard = errors.New("wildcard is not the left-most label")
+)
+
+// WildcardError is the error returned when a name cannot be covered by a certificate.
+//
+// Use errors.Is to check the reason of the failure against ErrWildcardPublicSuffix
+// or ErrInvalidWildcard. When the name cannot be parsed, the reason is a *ParseError.
+type WildcardError struct {
+	// Name is the name that failed the check.
+	Name string
+
+	// Err is the reason of the failure.
+	Err error
+}
+
+// Error implements error.
+func (e *WildcardError) Error() string {
+	return fmt.Sprintf("name %s: %v", e.Name, e.Err)
+}
+
+// Unwrap returns the reason of the failure.
+func (e *WildcardError) Unwrap() error {
+	return e.Err
+}
+
+// ErrUnsupportedScanType is returned when a DomainName or a Rule is scanned
+// from a database value that is neither a string nor a []byte.
+var ErrUnsupportedScanType = errors.New("unsupported scan type")
@@ -1,13 +1,15 @@
 // Package generator downloads an updated version of the PSL list and compiles it into go code.
 //
 // It is meant to be used by maintainers in conjunction with the go generate tool
-// to update the list.
+// to update the list. The list can also be compiled from a local copy,
+// for builds that cannot access the network or that need to pin a vetted version.
 package generator
 
 import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"go/format"
 	"io"
@@ -25,29 +27,87 @@ const (
 	list = `// This file is automatically generated
 // Run "go run cmd/gen/gen.go" to update the list.
 
-package publicsuffix
-
+package {{.Package}}
+{{if .Qualifier}}
+import "github.com/weppos/publicsuffix-go/publicsuffix"
+{{end}}
 const ListVersion = "PSL version {{.VersionSHA}} ({{.VersionDate}})"
-
-func DefaultRules() [{{len .Rules}}]Rule {
-	return r
+{{if .DeclareList}}
+var {{.ListVar}} = {{.Qualifier}}NewLazyList(func() []{{.Qualifier}}Rule {
+	rules := {{.RulesFunc}}()
+	return rules[:]
+})
+{{end}}
+func {{.RulesFunc}}() [{{len .Rules}}]{{.Qualifier}}Rule {
+	var rules [{{len .Rules}}]{{.Qualifier}}Rule
+	for i, e := range {{.RulesVar}} {
+		offset, size := e&0xffffff, e>>24&0xff
+		rules[i] = {{.Qualifier}}Rule{
+			Type:    int(e >> 32 & 0x3),
+			Value:   {{.TextVar}}[offset : offset+size],
+			Length:  int(e >> 51 & 0xff),
+			Private: e>>34&0x1 == 1,
+		}
+		{{if .Owners}} \
+		if j := e >> 35 & 0xffff; j > 0 {
+			rules[i].Owner = &{{.OwnersVar}}[j-1]
+		}
+		{{end}} \
+	}
+	return rules
 }
 
-var r = [{{len .Rules}}]Rule{
-	{{range $r := .Rules}} \
-	{ {{$r.Type}}, "{{$r.Value}}", {{$r.Length}}, {{$r.Private}} },
+{{if .Owners}} \
+var {{.OwnersVar}} = [{{len .Owners}}]{{.Qualifier}}Owner{
+	{{range $o := .Owners}} \
+	{Name: {{printf "%q" $o.Name}}, URL: {{printf "%q" $o.URL}}, Comment: {{printf "%q" $o.Comment}}, Line: {{$o.Line}} },
 	{{end}}
 }
 
-func init() {
-	for i := range r {
-		DefaultList.AddRule(&r[i])
-	}
+{{end}} \
+// {{.TextVar}} contains the values of all the rules.
+const {{.TextVar}} = "" +
+	{{range $t := .Text}} \
+	{{printf "%q" $t}} +
+	{{end}} \
+	""
+
+// {{.RulesVar}} contains the rules packed into integers, where the bits from the least significant are:
+//   - 0-23: offset of the value in {{.TextVar}}
+//   - 24-31: size of the value
+//   - 32-33: type
+//   - 34: private
+//   - 35-50: position of the owner in {{.OwnersVar}} plus one, or zero if the rule has no owner
+//   - 51-58: length
+var {{.RulesVar}} = [{{len .Rules}}]uint64{
+	{{range $r := .Rules}} \
+	{{printf "0x%016x" $r.Entry}}, // {{$r.Text}}
+	{{end}} \
 }
 
 `
 )
 
+// The bit layout of the packed rules.
+const (
+	packedOffsetBits = 24
+	packedSizeBits   = 8
+	packedOwnerBits  = 16
+	packedLengthBits = 8
+
+	packedSizeShift    = 24
+	packedTypeShift    = 32
+	packedPrivateShift = 34
+	packedOwnerShift   = 35
+	packedLengthShift  = 51
+
+	// textChunkSize is the size of each line of the text constant in the generated code.
+	textChunkSize = 80
+)
+
+// ErrOutOfDate is returned by Check when the generated file is not up to date.
+var ErrOutOfDate = errors.New("generated file is out of date")
+
 var listTmpl = template.Must(template.New("list").Parse(cont(list)))
 
 // https://github.com/golang/go/issues/9969
@@ -113,15 +173,145 @@ func extractHeadInfo(ctx context.Context) (*headInfo, error) {
 	}, nil
 }
 
+// packedRule is a rule packed into an integer, along with its Public Suffix representation.
+type packedRule struct {
+	Entry uint64
+	Text  string
+}
+
+// packRules packs the rules into a text that contains all the values,
+// and a list of integers that hold the position of each value in the text
+// along with the other fields of the rule.
+// The values that are already part of the text are not repeated.
+func packRules(rules []publicsuffix.Rule) ([]publicsuffix.Owner, string, []packedRule, error) {
+	var owners []publicsuffix.Owner
+	index := map[*publicsuffix.Owner]int{}
+	var text strings.Builder
+	packed := make([]packedRule, len(rules))
+
+	for i, rule := range rules {
+		offset := strings.Index(text.String(), rule.Value)
+		if offset < 0 {
+			offset = text.Len()
+			text.WriteString(rule.Value)
+		}
+
+		var owner int
+		if rule.Owner != nil {
+			j, ok := index[rule.Owner]
+			if !ok {
+				j = len(owners)
+				index[rule.Owner] = j
+				owners = append(owners, *rule.Owner)
+			}
+			owner = j + 1
+		}
+
+		switch {
+		case offset >= 1<<packedOffsetBits:
+			return nil, "", nil, fmt.Errorf("rule %s: text too large to pack", rule.Value)
+		case len(rule.Value) >= 1<<packedSizeBits:
+			return nil, "", nil, fmt.Errorf("rule %s: value too long to pack", rule.Value)
+		case owner >= 1<<packedOwnerBits:
+			return nil, "", nil, fmt.Errorf("rule %s: too many owners to pack", rule.Value)
+		case rule.Length >= 1<<packedLengthBits:
+			return nil, "", nil, fmt.Errorf("rule %s: too many labels to pack", rule.Value)
+		case rule.Type < 0 || rule.Type > 3:
+			return nil, "", nil, fmt.Errorf("rule %s: invalid type %d", rule.Value, rule.Type)
+		}
+
+		e := uint64(offset) |
+			uint64(len(rule.Value))<<packedSizeShift |
+			uint64(rule.Type)<<packedTypeShift |
+			uint64(owner)<<packedOwnerShift |
+			uint64(rule.Length)<<packedLengthShift
+		if rule.Private {
+			e |= 1 << packedPrivateShift
+		}
+		packed[i] = packedRule{Entry: e, Text: ruleText(rule)}
+	}
+
+	return owners, text.String(), packed, nil
+}
+
+// ruleText returns the Public Suffix representation of the rule.
+func ruleText(rule publicsuffix.Rule) string {
+	switch rule.Type {
+	case publicsuffix.WildcardType:
+		if rule.Value == "" {
+			return "*"
+		}
+		return "*." + rule.Value
+	case publicsuffix.ExceptionType:
+		return "!" + rule.Value
+	default:
+		return rule.Value
+	}
+}
+
+// chunk splits s into strings of at most size bytes.
+func chunk(s string, size int) []string {
+	var chunks []string
+	for len(s) > size {
+		chunks = append(chunks, s[:size])
+		s = s[size:]
+	}
+	if s != "" {
+		chunks = append(chunks, s)
+	}
+	return chunks
+}
+
 // Generator represents a generator.
 type Generator struct {
 	Verbose bool
+
+	// Source is the PSL to compile.
+	// When Source, SourceFile and SourceURL are all empty, the latest version of the PSL
+	// is downloaded from the publicsuffix/list GitHub repository.
+	Source io.Reader
+
+	// SourceFile is the path of a local copy of the PSL to compile.
+	// It is ignored when Source is set.
+	SourceFile string
+
+	// SourceURL is the URL of a copy of the PSL to compile, such as
+	// https://publicsuffix.org/list/public_suffix_list.dat.
+	// It is ignored when Source or SourceFile are set.
+	SourceURL string
+
+	// VersionSHA and VersionDate identify the version of a PSL read from
+	// Source, SourceFile or SourceURL. When empty, they are extracted from the
+	// "// COMMIT:" and "// VERSION:" header comments of the list.
+	VersionSHA  string
+	VersionDate time.Time
+
+	// Package is the name of the package of the generated code.
+	// When it is not "publicsuffix", the generated code imports the publicsuffix package
+	// and declares its own List.
+	Package string
+
+	// RulesFunc is the name of the function that returns the rules.
+	RulesFunc string
+
+	// RulesVar and OwnersVar are the names of the variables that hold the rules and their owners.
+	// The values of the rules are held in a constant named after RulesVar with the "Text" suffix.
+	RulesVar  string
+	OwnersVar string
+
+	// ListVar is the name of the List variable the rules are added to.
+	ListVar string
 }
 
 // NewGenerator creates a Generator with default settings.
 func NewGenerator() *Generator {
 	g := &Generator{
-		Verbose: false,
+		Verbose:   false,
+		Package:   "publicsuffix",
+		RulesFunc: "DefaultRules",
+		RulesVar:  "r",
+		OwnersVar: "o",
+		ListVar:   "DefaultList",
 	}
 	return g
 }
@@ -148,44 +338,89 @@ func (g *Generator) Print(ctx context.Context) error {
 	return err
 }
 
-// Generate downloads an updated version of the PSL list and compiles it into go code.
-func (g *Generator) generate(ctx context.Context) ([]byte, error) {
-	g.log("Fetching PSL version...\n")
-	headInfo, err := extractHeadInfo(ctx)
+// Check generates the code and compares it with the content of filename.
+// It returns ErrOutOfDate if the file doesn't exist or its content is different.
+func (g *Generator) Check(ctx context.Context, filename string) error {
+	content, err := g.generate(ctx)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	g.log("Downloading PSL %s...\n", headInfo.SHA[:6])
-	reqURL := fmt.Sprintf("https://raw.githubusercontent.com/publicsuffix/list/%s/public_suffix_list.dat", headInfo.SHA)
-
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	g.log("Checking %v...\n", filename)
+	current, err := os.ReadFile(filename)
+	if errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("%s: %w", filename, ErrOutOfDate)
+	}
 	if err != nil {
-		return nil, err
+		return err
+	}
+	if !bytes.Equal(current, content) {
+		return fmt.Errorf("%s: %w", filename, ErrOutOfDate)
 	}
+	return nil
+}
 
-	resp, err := http.DefaultClient.Do(req)
+// Generate reads the PSL list and compiles it into go code.
+//
+// The output depends only on the list and its version, therefore it is reproducible
+// when the list is read from a local source.
+func (g *Generator) generate(ctx context.Context) ([]byte, error) {
+	var src []byte
+	var info *headInfo
+	var err error
+
+	if g.Source != nil || g.SourceFile != "" || g.SourceURL != "" {
+		src, info, err = g.read(ctx)
+	} else {
+		src, info, err = g.download(ctx)
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	defer resp.Body.Close()
-
 	list := publicsuffix.NewList()
-	rules, err := list.Load(resp.Body, nil)
+	rules, err := list.Load(bytes.NewReader(src), nil)
 	if err != nil {
 		return nil, err
 	}
 
+	owners, text, packed, err := packRules(rules)
+	if err != nil {
+		return nil, err
+	}
 	data := struct {
 		VersionSHA  string
 		VersionDate string
-		Rules       []publicsuffix.Rule
+		Owners      []publicsuffix.Owner
+		Text        []string
+		Rules       []packedRule
+
+		Package     string
+		Qualifier   string
+		DeclareList bool
+		RulesFunc   string
+		RulesVar    string
+		OwnersVar   string
+		TextVar     string
+		ListVar     string
 	}{
-		headInfo.SHA[:6],
-		headInfo.Datetime.Format(time.ANSIC),
-		rules,
+		VersionSHA:  info.SHA[:min(len(info.SHA), 6)],
+		VersionDate: info.Datetime.Format(time.ANSIC),
+		Owners:      owners,
+		Text:        chunk(text, textChunkSize),
+		Rules:       packed,
+
+		Package:   g.Package,
+		RulesFunc: g.RulesFunc,
+		RulesVar:  g.RulesVar,
+		OwnersVar: g.OwnersVar,
+		TextVar:   g.RulesVar + "Text",
+		ListVar:   g.ListVar,
+	}
+	if g.Package != "publicsuffix" {
+		data.Qualifier = "publicsuffix."
 	}
+	data.DeclareList = data.Qualifier != "" || g.ListVar != "DefaultList"
 
 	g.log("Parsing PSL...\n")
 	buf := new(bytes.Buffer)
@@ -197,6 +432,122 @@ func (g *Generator) generate(ctx context.Context) ([]byte, error) {
 	return format.Source(buf.Bytes())
 }
 
+// download fetches the latest version of the PSL from GitHub.
+func (g *Generator) download(ctx context.Context) ([]byte, *headInfo, error) {
+	g.log("Fetching PSL version...\n")
+	headInfo, err := extractHeadInfo(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	g.log("Downloading PSL %s...\n", headInfo.SHA[:6])
+	reqURL := fmt.Sprintf("https://raw.githubusercontent.com/publicsuffix/list/%s/public_suffix_list.dat", headInfo.SHA)
+
+	src, err := fetch(ctx, reqURL)
+	if err != nil {
+		return nil, nil, err
+	}
+	return src, headInfo, nil
+}
+
+// fetch downloads the content at reqURL.
+func fetch(ctx context.Context, reqURL string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	return io.ReadAll(resp.Body)
+}
+
+// read reads the PSL from the source set in the generator.
+func (g *Generator) read(ctx context.Context) ([]byte, *headInfo, error) {
+	var src []byte
+	var err error
+
+	switch {
+	case g.Source != nil:
+		g.log("Reading PSL...\n")
+		src, err = io.ReadAll(g.Source)
+	case g.SourceFile != "":
+		g.log("Reading PSL from %v...\n", g.SourceFile)
+		src, err = os.ReadFile(g.SourceFile)
+	default:
+		g.log("Downloading PSL from %v...\n", g.SourceURL)
+		src, err = fetch(ctx, g.SourceURL)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	info, err := extractFileInfo(src)
+	if err != nil {
+		return nil, nil, err
+	}
+	if g.VersionSHA != "" {
+		info.SHA = g.VersionSHA
+	}
+	if !g.VersionDate.IsZero() {
+		info.Datetime = g.VersionDate
+	}
+
+	if info.SHA == "" {
+		return nil, nil, fmt.Errorf("missing PSL version: set VersionSHA or add a COMMIT header to the list")
+	}
+	if info.Datetime.IsZero() {
+		return nil, nil, fmt.Errorf("missing PSL date: set VersionDate or add a VERSION header to the list")
+	}
+	return src, info, nil
+}
+
+const (
+	fileHeaderVersion = "// VERSION:"
+	fileHeaderCommit  = "// COMMIT:"
+
+	// fileVersionLayout is the layout of the VERSION header, such as 2024-06-18_09-08-43_UTC.
+	fileVersionLayout = "2006-01-02_15-04-05_MST"
+)
+
+// extractFileInfo extracts the version of the PSL from the header comments of the list,
+// which are included in the copies published on publicsuffix.org.
+// The missing headers are left empty.
+func extractFileInfo(src []byte) (*headInfo, error) {
+	info := &headInfo{}
+
+	for _, line := range strings.Split(string(src), "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" && !strings.HasPrefix(line, "//") {
+			// the headers are at the top of the file, before the first rule
+			break
+		}
+
+		switch {
+		case strings.HasPrefix(line, fileHeaderCommit):
+			info.SHA = strings.TrimSpace(strings.TrimPrefix(line, fileHeaderCommit))
+		case strings.HasPrefix(line, fileHeaderVersion):
+			value := strings.TrimSpace(strings.TrimPrefix(line, fileHeaderVersion))
+			t, err := time.Parse(fileVersionLayout, value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid VERSION header %q: %w", value, err)
+			}
+			info.Datetime = t
+		}
+	}
+
+	return info, nil
+}
+
 func (g *Generator) log(format string, v ...interface{}) {
 	if !g.Verbose {
 		return
@@ -0,0 +1,243 @@
+package generator
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/weppos/publicsuffix-go/publicsuffix"
+)
+
+func TestGenerator_SourceFile(t *testing.T) {
+	g := NewGenerator()
+	g.SourceFile = "../../fixtures/list-version.txt"
+
+	content, err := g.generate(context.Background())
+	if err != nil {
+		t.Fatalf("generate returned an error: %v", err)
+	}
+
+	for _, want := range []string{
+		`const ListVersion = "PSL version 5db9b6 (Tue Jun 18 09:08:43 2024)"`,
+		"func DefaultRules() [3]Rule {",
+		`{Name: "Google, Inc.", URL: "", Comment: "Google, Inc.", Line: 20},`,
+		"0x0010000906000002, // com.ac",
+		"0x001000150c000008, // blogspot.com",
+	} {
+		if !bytes.Contains(content, []byte(want)) {
+			t.Errorf("generate output doesn't contain %v", want)
+		}
+	}
+
+	// the output is reproducible
+	again, err := g.generate(context.Background())
+	if err != nil {
+		t.Fatalf("generate returned an error: %v", err)
+	}
+	if !bytes.Equal(content, again) {
+		t.Errorf("generate output is not reproducible")
+	}
+}
+
+func TestGenerator_Source(t *testing.T) {
+	src, err := os.ReadFile("../../fixtures/list-simple.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g := NewGenerator()
+	g.Source = bytes.NewReader(src)
+	if _, err := g.generate(context.Background()); err == nil {
+		t.Errorf("generate should have returned an error for a list without version")
+	}
+
+	g = NewGenerator()
+	g.Source = bytes.NewReader(src)
+	g.VersionSHA = "e1b801aaaa"
+	g.VersionDate = time.Date(2026, 7, 25, 14, 19, 54, 0, time.UTC)
+	content, err := g.generate(context.Background())
+	if err != nil {
+		t.Fatalf("generate returned an error: %v", err)
+	}
+	if want := `const ListVersion = "PSL version e1b801 (Sat Jul 25 14:19:54 2026)"`; !strings.Contains(string(content), want) {
+		t.Errorf("generate output doesn't contain %v", want)
+	}
+}
+
+func TestGenerator_Package(t *testing.T) {
+	g := NewGenerator()
+	g.SourceFile = "../../fixtures/list-version.txt"
+	g.Package = "rules"
+	g.RulesFunc = "AllRules"
+	g.RulesVar = "all"
+	g.OwnersVar = "owners"
+	g.ListVar = "List"
+
+	content, err := g.generate(context.Background())
+	if err != nil {
+		t.Fatalf("generate returned an error: %v", err)
+	}
+
+	for _, want := range []string{
+		"package rules\n",
+		`import "github.com/weppos/publicsuffix-go/publicsuffix"`,
+		"var List = publicsuffix.NewLazyList(func() []publicsuffix.Rule {",
+		"rules := AllRules()",
+		"func AllRules() [3]publicsuffix.Rule {",
+		"var owners = [2]publicsuffix.Owner{",
+		"rules[i].Owner = &owners[j-1]",
+		`const allText = "" +`,
+		"var all = [3]uint64{",
+	} {
+		if !bytes.Contains(content, []byte(want)) {
+			t.Errorf("generate output doesn't contain %v", want)
+		}
+	}
+}
+
+func TestGenerator_TypeCheck(t *testing.T) {
+	if testing.Short() {
+		t.Skip("type-checking the publicsuffix package from source is slow")
+	}
+
+	testCases := []struct {
+		pkg     string
+		listVar string
+	}{
+		{"publicsuffix", "DefaultList"},
+		{"publicsuffix", "MyList"},
+		{"rules", "List"},
+	}
+
+	for _, testCase := range testCases {
+		g := NewGenerator()
+		g.SourceFile = "../../fixtures/list-version.txt"
+		g.Package = testCase.pkg
+		g.ListVar = testCase.listVar
+
+		content, err := g.generate(context.Background())
+		if err != nil {
+			t.Fatalf("generate returned an error: %v", err)
+		}
+		if err := typeCheck(content, testCase.pkg); err != nil {
+			t.Errorf("generate output with package %v and list %v doesn't compile: %v", testCase.pkg, testCase.listVar, err)
+		}
+	}
+}
+
+// typeCheck type-checks the generated code. In the publicsuffix package,
+// the code is checked along with the sources of the package, in place of rules.go.
+func typeCheck(content []byte, pkg string) error {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "rules.go", content, 0)
+	if err != nil {
+		return err
+	}
+	files := []*ast.File{f}
+
+	if pkg == "publicsuffix" {
+		paths, err := filepath.Glob("../*.go")
+		if err != nil {
+			return err
+		}
+		for _, path := range paths {
+			if strings.HasSuffix(path, "_test.go") || filepath.Base(path) == "rules.go" {
+				continue
+			}
+			f, err := parser.ParseFile(fset, path, nil, 0)
+			if err != nil {
+				return err
+			}
+			files = append(files, f)
+		}
+	}
+
+	conf := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	_, err = conf.Check(pkg, fset, files, nil)
+	return err
+}
+
+func TestGenerator_Check(t *testing.T) {
+	g := NewGenerator()
+	g.SourceFile = "../../fixtures/list-version.txt"
+
+	filename := t.TempDir() + "/rules.go"
+	if err := g.Check(context.Background(), filename); !errors.Is(err, ErrOutOfDate) {
+		t.Errorf("Check of a missing file error = %v, want %v", err, ErrOutOfDate)
+	}
+
+	if err := g.Write(context.Background(), filename); err != nil {
+		t.Fatalf("Write returned an error: %v", err)
+	}
+	if err := g.Check(context.Background(), filename); err != nil {
+		t.Errorf("Check returned an error: %v", err)
+	}
+
+	g.VersionSHA = "0123456789"
+	if err := g.Check(context.Background(), filename); !errors.Is(err, ErrOutOfDate) {
+		t.Errorf("Check of an outdated file error = %v, want %v", err, ErrOutOfDate)
+	}
+}
+
+func TestPackRules(t *testing.T) {
+	list := publicsuffix.NewList()
+	rules, err := list.LoadString(`// jp
+jp
+*.kobe.jp
+!city.kobe.jp
+kobe.jp
+
+// ===BEGIN PRIVATE DOMAINS===
+// Google, Inc.
+blogspot.com
+*
+`, nil)
+	if err != nil {
+		t.Fatalf("Parse returned an error: %v", err)
+	}
+
+	owners, text, packed, err := packRules(rules)
+	if err != nil {
+		t.Fatalf("packRules returned an error: %v", err)
+	}
+	if want, got := 2, len(owners); want != got {
+		t.Errorf("packRules returned %v owners, want %v", got, want)
+	}
+	// the values already in the text are not repeated
+	if want := "jpkobe.jpcity.kobe.jpblogspot.com"; text != want {
+		t.Errorf("packRules returned text %v, want %v", text, want)
+	}
+
+	// decode the rules like the generated code does
+	for i, p := range packed {
+		e := p.Entry
+		offset, size := e&0xffffff, e>>24&0xff
+		got := publicsuffix.Rule{
+			Type:    int(e >> 32 & 0x3),
+			Value:   text[offset : offset+size],
+			Length:  int(e >> 51 & 0xff),
+			Private: e>>34&0x1 == 1,
+		}
+		if j := e >> 35 & 0xffff; j > 0 {
+			got.Owner = &owners[j-1]
+		}
+
+		if want := rules[i]; !reflect.DeepEqual(want, got) {
+			t.Errorf("packRules[%v] = %+v, want %+v", i, got, want)
+		}
+		if want := ruleText(rules[i]); p.Text != want {
+			t.Errorf("packRules[%v] text = %v, want %v", i, p.Text, want)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package publicsuffix
+
+import (
+	"errors"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Host is a DomainName extracted from a URL, a host:port or an email address,
+// along with the parts of the input that were removed to extract the name.
+//
+// The DomainName is a named field rather than an embedded one, so that a Host
+// is marshaled with the Port and the User, rather than as the DomainName alone.
+type Host struct {
+	// Domain is the name extracted from the input.
+	Domain *DomainName
+
+	// Port is the port, without the colon, if any.
+	Port string
+
+	// User is the userinfo of a URL, as in "user:password",
+	// or the local part of an email address, if any.
+	User string
+}
+
+// ParseURL extracts the host of the URL, and decomposes it into TLD, SLD, TRD
+// using the default (Public Suffix) List.
+//
+// Examples:
+//
+//	u, _ := url.Parse("https://user@www.example.co.uk:8443/path")
+//	publicsuffix.ParseURL(u)
+//	// &Host{&DomainName{"co.uk", "example", "www"}, "8443", "user"}
+func ParseURL(u *url.URL) (*Host, error) {
+	return ParseURLFromListWithOptions(DefaultList, u, DefaultFindOptions)
+}
+
+// ParseURLFromListWithOptions is like ParseURL,
+// but uses the (Public Suffix) list and the options passed as arguments.
+//
+// The userinfo and the port are removed, as well as the brackets of an IPv6 address.
+// The host is then parsed with ParseFromListWithOptions, hence an IDN host
+// is converted to ASCII and an IP address is subject to FindOptions.AllowIP.
+func ParseURLFromListWithOptions(l *List, u *url.URL, options *FindOptions) (*Host, error) {
+	dn, err := ParseFromListWithOptions(l, u.Hostname(), options)
+	if err != nil {
+		return nil, err
+	}
+
+	h := &Host{Domain: dn, Port: u.Port()}
+	if u.User != nil {
+		h.User = u.User.String()
+	}
+	return h, nil
+}
+
+// ParseHostPort extracts the host from a host and an optional port,
+// such as the value of a Host header, and decomposes it into TLD, SLD, TRD
+// using the default (Public Suffix) List.
+//
+// Examples:
+//
+//	publicsuffix.ParseHostPort("www.example.com:8080")
+//	// &Host{&DomainName{"com", "example", "www"}, "8080", ""}
+//	publicsuffix.ParseHostPort("www.example.com")
+//	// &Host{&DomainName{"com", "example", "www"}, "", ""}
+func ParseHostPort(hostport string) (*Host, error) {
+	return ParseHostPortFromListWithOptions(DefaultList, hostport, DefaultFindOptions)
+}
+
+// ParseHostPortFromListWithOptions is like ParseHostPort,
+// but uses the (Public Suffix) list and the options passed as arguments.
+//
+// The host and the port are split with net.SplitHostPort, and the port is optional.
+// An IPv6 address must be enclosed in brackets when it is followed by a port, as in "[::1]:443".
+// A value that net.SplitHostPort cannot split, or a name enclosed in brackets, fails with
+// a *net.AddrError, and a port that is not a number between 0 and 65535 fails with ErrInvalidPort.
+func ParseHostPortFromListWithOptions(l *List, hostport string, options *FindOptions) (*Host, error) {
+	host, port := hostport, ""
+	if _, ok := parseIP(hostport); !ok {
+		var err error
+		host, port, err = net.SplitHostPort(hostport)
+		var aerr *net.AddrError
+		switch {
+		case err == nil:
+		case errors.As(err, &aerr) && aerr.Err == "missing port in address":
+			// there is no port
+			host, port = hostport, ""
+		default:
+			return nil, &ParseError{Name: hostport, Err: err}
+		}
+	}
+
+	// the brackets enclose an IP address only
+	if strings.HasPrefix(hostport, "[") {
+		if _, ok := parseIP(host); !ok {
+			return nil, &ParseError{Name: hostport, Err: &net.AddrError{Err: "unexpected '[' in address", Addr: hostport}}
+		}
+	}
+
+	if port != "" {
+		if _, err := strconv.ParseUint(port, 10, 16); err != nil {
+			return nil, &ParseError{Name: hostport, Err: ErrInvalidPort}
+		}
+	}
+
+	dn, err := ParseFromListWithOptions(l, host, options)
+	if err != nil {
+		return nil, err
+	}
+	return &Host{Domain: dn, Port: port}, nil
+}
+
+// ParseEmail extracts the domain of an email address, and decomposes it into TLD, SLD, TRD
+// using the default (Public Suffix) List.
+//
+// Examples:
+//
+//	publicsuffix.ParseEmail("john@mail.example.co.uk")
+//	// &Host{&DomainName{"co.uk", "example", "mail"}, "", "john"}
+func ParseEmail(address string) (*Host, error) {
+	return ParseEmailFromListWithOptions(DefaultList, address, DefaultFindOptions)
+}
+
+// ParseEmailFromListWithOptions is like ParseEmail,
+// but uses the (Public Suffix) list and the options passed as arguments.
+//
+// The address is expected in the local@domain form, without a display name:
+// use net/mail to parse a complete address first. The domain is the part after the last "@",
+// and it can be a domain literal such as "[192.168.0.1]".
+// If the address has no "@", the error is a *ParseError that wraps ErrNoAtSign.
+func ParseEmailFromListWithOptions(l *List, address string, options *FindOptions) (*Host, error) {
+	i := strings.LastIndexByte(address, '@')
+	if i < 0 {
+		return nil, &ParseError{Name: address, Err: ErrNoAtSign}
+	}
+
+	dn, err := ParseFromListWithOptions(l, address[i+1:], options)
+	if err != nil {
+		return nil, err
+	}
+	return &Host{Domain: dn, User: address[:i]}, nil
+}
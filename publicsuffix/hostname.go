@@ -0,0 +1,109 @@
+package publicsuffix
+
+import (
+	"strings"
+)
+
+const (
+	// maxNameLength is the maximum length of a name, in octets, excluding the root dot.
+	maxNameLength = 253
+
+	// maxLabelLength is the maximum length of a label, in octets.
+	maxLabelLength = 63
+)
+
+// DefaultHostnameValidation validates the names according to the LDH rule:
+// labels of letters, digits and hyphens, that don't start or end with a hyphen.
+var DefaultHostnameValidation = &HostnameValidation{}
+
+// HostnameValidation are the rules used to validate a name before the lookup.
+//
+// The zero value enforces the LDH rule of RFC 1123. Every name is also checked
+// for empty labels, the length of the labels and the length of the name.
+type HostnameValidation struct {
+	// Set to true to allow underscores, as in "_dmarc.example.com".
+	// Default to false, which means underscores are rejected.
+	AllowUnderscore bool
+
+	// Set to true to allow labels that start or end with a hyphen.
+	// Default to false, which means these labels are rejected.
+	AllowEdgeHyphen bool
+
+	// Set to true to skip the LDH rule, and allow any character other than a dot.
+	// AllowUnderscore and AllowEdgeHyphen have no effect when it is set.
+	// Default to false, which means only letters, digits and hyphens are allowed.
+	AllowNonLDH bool
+}
+
+// check validates the name, which is expected to be lowercase and encoded in ASCII.
+// It returns a *HostnameError describing the first problem found.
+func (v *HostnameValidation) check(name string) error {
+	if i := strings.LastIndexByte(name, ':'); i >= 0 && isPort(name[i+1:]) && strings.IndexByte(name[:i], ':') < 0 {
+		return &HostnameError{Offset: i, Err: ErrHasPort}
+	}
+	if len(name) > maxNameLength {
+		return &HostnameError{Offset: maxNameLength, Err: ErrNameTooLong}
+	}
+
+	for offset := 0; offset <= len(name); {
+		label := name[offset:]
+		if i := strings.IndexByte(label, '.'); i >= 0 {
+			label = label[:i]
+		}
+
+		switch {
+		case label == "":
+			return &HostnameError{Offset: offset, Err: ErrEmptyLabel}
+		case len(label) > maxLabelLength:
+			return &HostnameError{Label: label, Offset: offset, Err: ErrLabelTooLong}
+		}
+		if err := v.checkLabel(label); err != nil {
+			err.Offset += offset
+			return err
+		}
+
+		offset += len(label) + 1
+	}
+	return nil
+}
+
+// checkLabel validates a single label,
+// and returns the offset of the problem relative to the label.
+func (v *HostnameValidation) checkLabel(label string) *HostnameError {
+	if v.AllowNonLDH {
+		return nil
+	}
+
+	for i := 0; i < len(label); i++ {
+		c := label[i]
+		switch {
+		case 'a' <= c && c <= 'z', '0' <= c && c <= '9', c == '-':
+		case c == '_' && v.AllowUnderscore:
+		default:
+			return &HostnameError{Label: label, Offset: i, Err: ErrInvalidCharacter}
+		}
+	}
+
+	if !v.AllowEdgeHyphen {
+		if label[0] == '-' {
+			return &HostnameError{Label: label, Err: ErrInvalidHyphen}
+		}
+		if label[len(label)-1] == '-' {
+			return &HostnameError{Label: label, Offset: len(label) - 1, Err: ErrInvalidHyphen}
+		}
+	}
+	return nil
+}
+
+// isPort reports whether s is a non-empty sequence of digits.
+func isPort(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,95 @@
+package publicsuffix
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestHostnameValidation(t *testing.T) {
+	label63 := strings.Repeat("a", 63)
+	label64 := strings.Repeat("a", 64)
+	name253 := strings.Repeat(label63+".", 3) + strings.Repeat("a", 61)
+
+	testCases := []struct {
+		name       string
+		validation *HostnameValidation
+		err        error
+		label      string
+		offset     int
+	}{
+		{"www.example.com", DefaultHostnameValidation, nil, "", 0},
+		{"www-1.example.com", DefaultHostnameValidation, nil, "", 0},
+		{label63 + ".com", DefaultHostnameValidation, nil, "", 0},
+		{name253, DefaultHostnameValidation, nil, "", 0},
+
+		{"a..com", DefaultHostnameValidation, ErrEmptyLabel, "", 2},
+		{"example.com.", DefaultHostnameValidation, ErrEmptyLabel, "", 12},
+		{label64 + ".com", DefaultHostnameValidation, ErrLabelTooLong, label64, 0},
+		{name253 + "a", DefaultHostnameValidation, ErrNameTooLong, "", 253},
+		{"www.example.com:8080", DefaultHostnameValidation, ErrHasPort, "", 15},
+		{"www.exa mple.com", DefaultHostnameValidation, ErrInvalidCharacter, "exa mple", 7},
+		{"::1", DefaultHostnameValidation, ErrInvalidCharacter, "::1", 0},
+
+		{"_dmarc.example.com", DefaultHostnameValidation, ErrInvalidCharacter, "_dmarc", 0},
+		{"_dmarc.example.com", &HostnameValidation{AllowUnderscore: true}, nil, "", 0},
+
+		{"www.-example.com", DefaultHostnameValidation, ErrInvalidHyphen, "-example", 4},
+		{"www.example-.com", DefaultHostnameValidation, ErrInvalidHyphen, "example-", 11},
+		{"www.example-.com", &HostnameValidation{AllowEdgeHyphen: true}, nil, "", 0},
+
+		{"*.exa mple.com", &HostnameValidation{AllowNonLDH: true}, nil, "", 0},
+		{"a..com", &HostnameValidation{AllowNonLDH: true}, ErrEmptyLabel, "", 2},
+	}
+
+	for _, testCase := range testCases {
+		err := testCase.validation.check(testCase.name)
+		if !errors.Is(err, testCase.err) {
+			t.Errorf("check(%v) = %v, want %v", testCase.name, err, testCase.err)
+			continue
+		}
+		if testCase.err == nil {
+			continue
+		}
+
+		var herr *HostnameError
+		if !errors.As(err, &herr) {
+			t.Errorf("check(%v) error %T is not a *HostnameError", testCase.name, err)
+			continue
+		}
+		if herr.Label != testCase.label || herr.Offset != testCase.offset {
+			t.Errorf("check(%v) = %#v, want label %q at offset %d", testCase.name, herr, testCase.label, testCase.offset)
+		}
+	}
+}
+
+func TestParseFromListWithOptions_Hostname(t *testing.T) {
+	list := NewList()
+	_ = list.AddRule(MustNewRule("com"))
+
+	options := &FindOptions{Hostname: DefaultHostnameValidation}
+
+	if _, err := ParseFromListWithOptions(list, "WWW.Example.com", options); err != nil {
+		t.Errorf("ParseFromListWithOptions() returned error: %v", err)
+	}
+	if _, err := ParseFromListWithOptions(list, "a..com", nil); err != nil {
+		t.Errorf("ParseFromListWithOptions() without validation returned error: %v", err)
+	}
+
+	_, err := ParseFromListWithOptions(list, "www.example.com:443", options)
+	if !errors.Is(err, ErrHasPort) {
+		t.Fatalf("ParseFromListWithOptions() error = %v, want %v", err, ErrHasPort)
+	}
+	if want, got := "name www.example.com:443: name has a port at offset 15", err.Error(); want != got {
+		t.Errorf("ParseFromListWithOptions() error message = %v, want %v", got, want)
+	}
+
+	_, err = ParseFromListWithOptions(list, "foo_bar.example.com", options)
+	if want, got := `name foo_bar.example.com: label "foo_bar" at offset 3: invalid character`, err.Error(); want != got {
+		t.Errorf("ParseFromListWithOptions() error message = %v, want %v", got, want)
+	}
+	var perr *ParseError
+	if !errors.As(err, &perr) || perr.Name != "foo_bar.example.com" {
+		t.Errorf("ParseFromListWithOptions() error = %#v, want a *ParseError", err)
+	}
+}
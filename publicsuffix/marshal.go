@@ -0,0 +1,194 @@
+package publicsuffix
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding"
+	"encoding/json"
+	"fmt"
+)
+
+var (
+	_ encoding.TextMarshaler   = DomainName{}
+	_ encoding.TextUnmarshaler = (*DomainName)(nil)
+	_ json.Marshaler           = DomainName{}
+	_ json.Unmarshaler         = (*DomainName)(nil)
+	_ sql.Scanner              = (*DomainName)(nil)
+	_ driver.Valuer            = DomainName{}
+
+	_ encoding.TextMarshaler   = Rule{}
+	_ encoding.TextUnmarshaler = (*Rule)(nil)
+	_ json.Marshaler           = Rule{}
+	_ json.Unmarshaler         = (*Rule)(nil)
+	_ sql.Scanner              = (*Rule)(nil)
+	_ driver.Valuer            = RuleValuer{}
+)
+
+// unmarshalFindOptions are the options used to parse a DomainName back from its text,
+// so that the IP addresses and the root dot written by String round-trip.
+var unmarshalFindOptions = &FindOptions{IgnorePrivate: false, DefaultRule: DefaultRule, AllowIP: true, KeepRootDot: true}
+
+// MarshalText implements encoding.TextMarshaler.
+// The text is the same as String.
+func (d DomainName) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+// The text is parsed against the DefaultList, and an empty text results in an empty DomainName.
+//
+// Use UnmarshalTextFromListWithOptions to parse the text against another list.
+func (d *DomainName) UnmarshalText(text []byte) error {
+	return d.UnmarshalTextFromListWithOptions(DefaultList, text, unmarshalFindOptions)
+}
+
+// UnmarshalTextFromListWithOptions is like UnmarshalText,
+// but parses the text with the (Public Suffix) list and the options passed as arguments.
+// Set FindOptions.AllowIP and FindOptions.KeepRootDot for IP addresses and fully-qualified names to round-trip.
+func (d *DomainName) UnmarshalTextFromListWithOptions(l *List, text []byte, options *FindOptions) error {
+	if len(text) == 0 {
+		*d = DomainName{}
+		return nil
+	}
+
+	dn, err := ParseFromListWithOptions(l, string(text), options)
+	if err != nil {
+		return err
+	}
+	*d = *dn
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler.
+// The domain name is encoded as a JSON string.
+func (d DomainName) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+// The JSON string is parsed as in UnmarshalText, and null is a no-op.
+func (d *DomainName) UnmarshalJSON(data []byte) error {
+	text, err := unmarshalJSONString(data)
+	if err != nil || text == nil {
+		return err
+	}
+	return d.UnmarshalText(text)
+}
+
+// Scan implements sql.Scanner.
+// A string or []byte value is parsed as in UnmarshalText, and NULL results in an empty DomainName.
+func (d *DomainName) Scan(src any) error {
+	text, err := scanText(src, "DomainName")
+	if err != nil {
+		return err
+	}
+	return d.UnmarshalText(text)
+}
+
+// Value implements driver.Valuer.
+// The domain name is stored as a string, and an empty DomainName as NULL.
+func (d DomainName) Value() (driver.Value, error) {
+	if s := d.String(); s != "" {
+		return s, nil
+	}
+	return nil, nil
+}
+
+// MarshalText implements encoding.TextMarshaler.
+// The text is the rule as it appears in the Public Suffix list, such as "*.uk" or "!parliament.uk".
+func (r Rule) MarshalText() ([]byte, error) {
+	return []byte(r.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+// The text is parsed with NewRule, and an empty text results in an empty Rule.
+//
+// The text carries neither the section nor the owner of the rule,
+// hence Private is false and Owner is nil.
+func (r *Rule) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		*r = Rule{}
+		return nil
+	}
+
+	rule, err := NewRule(string(text))
+	if err != nil {
+		return err
+	}
+	*r = *rule
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler.
+// The rule is encoded as a JSON string, as in MarshalText.
+func (r Rule) MarshalJSON() ([]byte, error) {
+	return json.Marshal(r.String())
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+// The JSON string is parsed as in UnmarshalText, and null is a no-op.
+func (r *Rule) UnmarshalJSON(data []byte) error {
+	text, err := unmarshalJSONString(data)
+	if err != nil || text == nil {
+		return err
+	}
+	return r.UnmarshalText(text)
+}
+
+// Scan implements sql.Scanner.
+// A string or []byte value is parsed as in UnmarshalText, and NULL results in an empty Rule.
+func (r *Rule) Scan(src any) error {
+	text, err := scanText(src, "Rule")
+	if err != nil {
+		return err
+	}
+	return r.UnmarshalText(text)
+}
+
+// RuleValuer stores a Rule in a database.
+//
+// Rule cannot implement driver.Valuer, since the method would clash with its Value field.
+// Pass RuleValuer{r} as a query argument instead, and scan the column into the *Rule.
+type RuleValuer struct {
+	Rule *Rule
+}
+
+// Value implements driver.Valuer.
+// The rule is stored as a string, as in MarshalText, and a nil or empty Rule as NULL.
+func (v RuleValuer) Value() (driver.Value, error) {
+	if v.Rule == nil {
+		return nil, nil
+	}
+	if s := v.Rule.String(); s != "" {
+		return s, nil
+	}
+	return nil, nil
+}
+
+// unmarshalJSONString decodes a JSON string.
+// It returns nil for null, and a non-nil slice otherwise.
+func unmarshalJSONString(data []byte) ([]byte, error) {
+	if string(data) == "null" {
+		return nil, nil
+	}
+
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, err
+	}
+	return []byte(s), nil
+}
+
+// scanText returns the text of a database value, or an empty text for NULL.
+func scanText(src any, target string) ([]byte, error) {
+	switch src := src.(type) {
+	case nil:
+		return []byte{}, nil
+	case string:
+		return []byte(src), nil
+	case []byte:
+		return src, nil
+	default:
+		return nil, fmt.Errorf("%w %T into %s", ErrUnsupportedScanType, src, target)
+	}
+}
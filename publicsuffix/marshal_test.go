@@ -0,0 +1,232 @@
+package publicsuffix
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"net/netip"
+	"reflect"
+	"testing"
+)
+
+func TestDomainNameJSON(t *testing.T) {
+	type record struct {
+		Domain *DomainName `json:"domain"`
+	}
+
+	for _, testCase := range validTestCases {
+		dn, err := Parse(testCase.input)
+		if err != nil {
+			t.Fatalf("Parse(%v) returned error: %v", testCase.input, err)
+		}
+
+		data, err := json.Marshal(record{Domain: dn})
+		if err != nil {
+			t.Fatalf("json.Marshal(%v) returned error: %v", testCase.input, err)
+		}
+		if got, want := string(data), `{"domain":"`+testCase.input+`"}`; got != want {
+			t.Errorf("json.Marshal(%v) = %v, want %v", testCase.input, got, want)
+		}
+
+		var got record
+		if err := json.Unmarshal(data, &got); err != nil {
+			t.Fatalf("json.Unmarshal(%s) returned error: %v", data, err)
+		}
+		if !reflect.DeepEqual(got.Domain, dn) {
+			t.Errorf("json.Unmarshal(%s) = %+v, want %+v", data, got.Domain, dn)
+		}
+	}
+}
+
+func TestDomainNameJSON_Value(t *testing.T) {
+	type record struct {
+		Domain DomainName `json:"domain"`
+		Rule   Rule       `json:"rule"`
+	}
+
+	dn, _ := Parse("www.example.co.uk")
+	want := record{Domain: *dn, Rule: *MustNewRule("*.kawasaki.jp")}
+
+	data, err := json.Marshal(want)
+	if err != nil {
+		t.Fatalf("json.Marshal() returned error: %v", err)
+	}
+	if got, want := string(data), `{"domain":"www.example.co.uk","rule":"*.kawasaki.jp"}`; got != want {
+		t.Errorf("json.Marshal() = %v, want %v", got, want)
+	}
+
+	var got record
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("json.Unmarshal(%s) returned error: %v", data, err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("json.Unmarshal(%s) = %+v, want %+v", data, got, want)
+	}
+}
+
+func TestHostJSON(t *testing.T) {
+	h, err := ParseHostPort("www.example.com:8080")
+	if err != nil {
+		t.Fatalf("ParseHostPort() returned error: %v", err)
+	}
+	h.User = "john"
+
+	data, err := json.Marshal(h)
+	if err != nil {
+		t.Fatalf("json.Marshal() returned error: %v", err)
+	}
+	if got, want := string(data), `{"Domain":"www.example.com","Port":"8080","User":"john"}`; got != want {
+		t.Errorf("json.Marshal() = %v, want %v", got, want)
+	}
+
+	var got Host
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("json.Unmarshal(%s) returned error: %v", data, err)
+	}
+	if !reflect.DeepEqual(&got, h) {
+		t.Errorf("json.Unmarshal(%s) = %+v, want %+v", data, got, h)
+	}
+
+	// a Host without a domain name
+	data, err = json.Marshal(Host{Port: "1"})
+	if err != nil {
+		t.Fatalf("json.Marshal() returned error: %v", err)
+	}
+	if got, want := string(data), `{"Domain":null,"Port":"1","User":""}`; got != want {
+		t.Errorf("json.Marshal() = %v, want %v", got, want)
+	}
+}
+
+func TestDomainNameText_RoundTrip(t *testing.T) {
+	testCases := []*DomainName{
+		{TLD: "co.uk", SLD: "example", TRD: "www", Rule: MustNewRule("*.uk"), RootDot: true},
+		{IP: netip.MustParseAddr("2001:db8::1")},
+		{},
+	}
+
+	for _, dn := range testCases {
+		text, err := dn.MarshalText()
+		if err != nil {
+			t.Fatalf("MarshalText(%v) returned error: %v", dn, err)
+		}
+
+		got := &DomainName{}
+		if err := got.UnmarshalText(text); err != nil {
+			t.Fatalf("UnmarshalText(%s) returned error: %v", text, err)
+		}
+		if got.String() != dn.String() || got.RootDot != dn.RootDot || got.IP != dn.IP {
+			t.Errorf("UnmarshalText(%s) = %+v, want %+v", text, got, dn)
+		}
+	}
+}
+
+func TestDomainNameUnmarshalText_Errors(t *testing.T) {
+	dn := &DomainName{}
+	if err := dn.UnmarshalText([]byte("co.uk")); !errors.Is(err, ErrIsPublicSuffix) {
+		t.Errorf("UnmarshalText(co.uk) error = %v, want %v", err, ErrIsPublicSuffix)
+	}
+	if err := json.Unmarshal([]byte(`42`), dn); err == nil {
+		t.Errorf("json.Unmarshal(42) should have returned an error")
+	}
+
+	// null leaves the domain name unchanged
+	dn, _ = Parse("www.example.com")
+	if err := json.Unmarshal([]byte(`null`), dn); err != nil || dn.String() != "www.example.com" {
+		t.Errorf("json.Unmarshal(null) = %v, %v, want www.example.com, nil", dn, err)
+	}
+}
+
+func TestDomainNameUnmarshalTextFromListWithOptions(t *testing.T) {
+	list := NewList()
+	_ = list.AddRule(MustNewRule("example"))
+
+	dn := &DomainName{}
+	if err := dn.UnmarshalTextFromListWithOptions(list, []byte("www.foo.example"), nil); err != nil {
+		t.Fatalf("UnmarshalTextFromListWithOptions() returned error: %v", err)
+	}
+	if want := (&DomainName{TLD: "example", SLD: "foo", TRD: "www", Rule: list.Find("example", nil)}); !reflect.DeepEqual(dn, want) {
+		t.Errorf("UnmarshalTextFromListWithOptions() = %+v, want %+v", dn, want)
+	}
+
+	if err := dn.UnmarshalTextFromListWithOptions(list, []byte("www.example.com"), &FindOptions{}); !errors.Is(err, ErrNoRuleMatch) {
+		t.Errorf("UnmarshalTextFromListWithOptions() error = %v, want %v", err, ErrNoRuleMatch)
+	}
+}
+
+func TestDomainNameSQL(t *testing.T) {
+	dn, _ := Parse("www.example.co.uk")
+	value, err := dn.Value()
+	if err != nil || value != "www.example.co.uk" {
+		t.Errorf("Value() = %v, %v, want www.example.co.uk, nil", value, err)
+	}
+
+	for _, src := range []any{"www.example.co.uk", []byte("www.example.co.uk")} {
+		got := &DomainName{}
+		if err := got.Scan(src); err != nil {
+			t.Fatalf("Scan(%v) returned error: %v", src, err)
+		}
+		if !reflect.DeepEqual(got, dn) {
+			t.Errorf("Scan(%v) = %+v, want %+v", src, got, dn)
+		}
+	}
+
+	// NULL
+	if value, err := (&DomainName{}).Value(); value != nil || err != nil {
+		t.Errorf("Value() of an empty DomainName = %v, %v, want nil, nil", value, err)
+	}
+	if value, err := driver.DefaultParameterConverter.ConvertValue((*DomainName)(nil)); value != nil || err != nil {
+		t.Errorf("ConvertValue() of a nil DomainName = %v, %v, want nil, nil", value, err)
+	}
+	got := &DomainName{TLD: "com", SLD: "example"}
+	if err := got.Scan(nil); err != nil || !reflect.DeepEqual(got, &DomainName{}) {
+		t.Errorf("Scan(nil) = %+v, %v, want empty DomainName, nil", got, err)
+	}
+
+	if err := got.Scan(42); !errors.Is(err, ErrUnsupportedScanType) {
+		t.Errorf("Scan(42) error = %v, want %v", err, ErrUnsupportedScanType)
+	}
+}
+
+func TestRuleJSON(t *testing.T) {
+	for _, content := range []string{"com", "co.uk", "*.uk", "!parliament.uk", "*"} {
+		rule := MustNewRule(content)
+
+		data, err := json.Marshal(rule)
+		if err != nil {
+			t.Fatalf("json.Marshal(%v) returned error: %v", content, err)
+		}
+		if got, want := string(data), `"`+content+`"`; got != want {
+			t.Errorf("json.Marshal(%v) = %v, want %v", content, got, want)
+		}
+
+		got := &Rule{}
+		if err := json.Unmarshal(data, got); err != nil {
+			t.Fatalf("json.Unmarshal(%s) returned error: %v", data, err)
+		}
+		if !reflect.DeepEqual(got, rule) {
+			t.Errorf("json.Unmarshal(%s) = %+v, want %+v", data, got, rule)
+		}
+	}
+}
+
+func TestRuleSQL(t *testing.T) {
+	rule := MustNewRule("!city.kawasaki.jp")
+	value, err := RuleValuer{rule}.Value()
+	if err != nil || value != "!city.kawasaki.jp" {
+		t.Errorf("RuleValuer.Value() = %v, %v, want !city.kawasaki.jp, nil", value, err)
+	}
+	if value, err := (RuleValuer{}).Value(); value != nil || err != nil {
+		t.Errorf("RuleValuer.Value() of a nil Rule = %v, %v, want nil, nil", value, err)
+	}
+
+	got := &Rule{}
+	if err := got.Scan([]byte("!city.kawasaki.jp")); err != nil || !reflect.DeepEqual(got, rule) {
+		t.Errorf("Scan() = %+v, %v, want %+v, nil", got, err, rule)
+	}
+	if err := got.Scan(nil); err != nil || !reflect.DeepEqual(got, &Rule{}) {
+		t.Errorf("Scan(nil) = %+v, %v, want empty Rule, nil", got, err)
+	}
+	if err := got.Scan(1.5); !errors.Is(err, ErrUnsupportedScanType) {
+		t.Errorf("Scan(1.5) error = %v, want %v", err, ErrUnsupportedScanType)
+	}
+}
@@ -1,4 +1,4 @@
-//go:generate go run ../cmd/gen/gen.go
+//go:generate go run ../cmd/gen/gen.go -v
 
 // Package publicsuffix provides a domain name parser
 // based on data from the public suffix list http://publicsuffix.org/.
@@ -7,13 +7,21 @@ package publicsuffix
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
 	"io"
+	"maps"
 	"net/http/cookiejar"
+	"net/netip"
 	"os"
+	"slices"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"unicode/utf8"
 
 	"golang.org/x/net/idna"
+	"golang.org/x/text/width"
 )
 
 const (
@@ -31,10 +39,22 @@ const (
 
 	listTokenPrivateDomains = "===BEGIN PRIVATE DOMAINS==="
 	listTokenComment        = "//"
+
+	listMarkerBeginICANN   = "// ===BEGIN ICANN DOMAINS==="
+	listMarkerEndICANN     = "// ===END ICANN DOMAINS==="
+	listMarkerBeginPrivate = "// " + listTokenPrivateDomains
+	listMarkerEndPrivate   = "// ===END PRIVATE DOMAINS==="
 )
 
-// DefaultList is the default List and it is used by Parse and Domain.
-var DefaultList = NewList()
+// DefaultList is the default List and it is used by Parse, Domain and CookieJarList.
+//
+// To update the rules at runtime, use DefaultList.Replace rather than assigning
+// a new List to the variable: Replace is safe for concurrent use and it is
+// visible to every function that uses the default List.
+var DefaultList = NewLazyList(func() []Rule {
+	rules := DefaultRules()
+	return rules[:]
+})
 
 // DefaultRule is the default Rule that represents "*".
 var DefaultRule = MustNewRule("*")
@@ -51,6 +71,44 @@ type Rule struct {
 	Value   string
 	Length  int
 	Private bool
+
+	// Owner describes the organization that operates the rule,
+	// when the rule is parsed from a commented source.
+	Owner *Owner
+}
+
+// Owner represents the comment block that precedes a group of rules
+// in a Public Suffix source. In the private section, it identifies
+// the organization that submitted the rules.
+//
+// Rules in the same group share the same *Owner.
+type Owner struct {
+	// Name is the name of the organization, taken from the first line of the comment block,
+	// such as "Amazon" in "// Amazon : https://www.amazon.com/".
+	Name string
+
+	// URL is the text that follows the name on the first line of the comment block, if any.
+	URL string
+
+	// Comment is the entire comment block, without the comment markers.
+	Comment string
+
+	// Line is the line number of the first line of the comment block.
+	Line int
+}
+
+// newOwner creates an Owner from the lines of a comment block.
+func newOwner(block []string, line int) *Owner {
+	lines := make([]string, len(block))
+	for i, text := range block {
+		lines[i] = strings.TrimSpace(strings.TrimPrefix(text, listTokenComment))
+	}
+
+	o := &Owner{Name: lines[0], Comment: strings.Join(lines, "\n"), Line: line}
+	if name, url, ok := strings.Cut(lines[0], " : "); ok {
+		o.Name, o.URL = strings.TrimSpace(name), strings.TrimSpace(url)
+	}
+	return o
 }
 
 // ParserOption are the options you can use to customize the way a List
@@ -65,6 +123,18 @@ type ParserOption struct {
 	// Default to false, which means the list is containing Unicode domains.
 	// This is the default because the original PSL currently contains Unicode.
 	ASCIIEncoded bool
+
+	// Set to true to validate the entire source before loading it.
+	// In strict mode the parser reports every invalid, duplicate or misplaced rule
+	// as a *LineError, and the list is modified only if no problem is found.
+	// Default to false, which means the parser stops at the first rule it cannot parse.
+	Strict bool
+
+	// The IDNA profile used to convert the rules from Unicode (U-labels) to ASCII (A-labels),
+	// such as idna.Lookup, idna.Registration, idna.Display or a custom profile.
+	// It is ignored when ASCIIEncoded is true.
+	// Default to nil, which means idna.Punycode, the profile used by ToASCII.
+	IDNA *idna.Profile
 }
 
 // FindOptions are the options you can use to customize the way a Rule
@@ -77,19 +147,122 @@ type FindOptions struct {
 	// The format Public Suffix algorithm states that the rule "*" should be used when no other rule matches,
 	// but some consumers may have different needs.
 	DefaultRule *Rule
+
+	// Set to true to return the domain encoded in Unicode (U-labels)
+	// from Domain and DomainFromListWithOptions.
+	// Default to false, which means the domain is returned in ASCII (A-labels),
+	// the same form used to match the rules. List.Find ignores this option.
+	Unicode bool
+
+	// The IDNA profile used to convert the name to ASCII (A-labels) before the lookup,
+	// and the domain to Unicode (U-labels) when Unicode is true.
+	// When set, every name is processed with the profile, including names that are already ASCII,
+	// so that the profile can validate them. List.Find ignores this option.
+	// Default to nil, which means idna.Punycode, the profile used by ToASCII,
+	// and only names that contain Unicode characters are converted.
+	IDNA *idna.Profile
+
+	// The rules used to validate the name, once converted to ASCII, before the lookup,
+	// such as DefaultHostnameValidation. List.Find ignores this option.
+	// Default to nil, which means the name is not validated.
+	Hostname *HostnameValidation
+
+	// Set to true to keep the root dot of a fully-qualified name, as in "www.example.com.",
+	// in the DomainName and in the domain returned by DomainFromListWithOptions.
+	// Default to false, which means the root dot is removed.
+	KeepRootDot bool
+
+	// Set to true to accept IPv4 and IPv6 address literals, such as "192.168.0.1" or "[::1]",
+	// and return a DomainName with only the IP set. List.Find ignores this option.
+	// Default to false, which means an IP address literal fails with ErrIPAddress.
+	AllowIP bool
 }
 
 // List represents a Public Suffix List.
+//
+// A List is safe for concurrent use by multiple goroutines.
+// The lookups don't take any lock: the changes to the list are applied to a copy
+// of the rules, which then replaces the current ones.
 type List struct {
-	// rules is kept private because you should not access rules directly
-	rules map[string]*Rule
+	// mu serializes the changes to the list
+	mu sync.Mutex
+
+	// lazy returns the rules to add the first time the list is used, if set.
+	lazy func() []Rule
+	once sync.Once
+
+	// rules is kept private because you should not access rules directly.
+	// The ruleSet is never modified once stored.
+	rules atomic.Pointer[ruleSet]
+}
+
+// ruleSet holds the rules of a List, indexed by Value.
+type ruleSet struct {
+	entries map[string]*entry
+	size    int
+}
+
+// entry holds the rules of a List that share the same Value.
+//
+// An entry holds at most one rule per type, because a normal rule such as "foo.example",
+// a wildcard rule such as "*.foo.example" and an exception rule such as "!foo.example"
+// all share the same Value.
+type entry struct {
+	normal    *Rule
+	wildcard  *Rule
+	exception *Rule
+
+	// suffixException reports whether an exception rule exists for a suffix of the Value,
+	// as "!www.ck" for "a.www.ck". Find stops at the first match unless it's set.
+	suffixException bool
+}
+
+// slot returns the pointer to the entry field that holds the rules of type t.
+// Unknown types are stored along with the normal rules.
+func (e *entry) slot(t int) **Rule {
+	switch t {
+	case WildcardType:
+		return &e.wildcard
+	case ExceptionType:
+		return &e.exception
+	default:
+		return &e.normal
+	}
 }
 
 // NewList creates a new empty list.
 func NewList() *List {
-	return &List{
-		rules: map[string]*Rule{},
+	l := &List{}
+	l.rules.Store(&ruleSet{entries: map[string]*entry{}})
+	return l
+}
+
+// NewLazyList creates a new list that is initialized with the rules returned by fn
+// the first time the list is used, rather than when it is created.
+//
+// It is meant for large, compiled lists such as DefaultList, so that programs
+// that never perform a lookup don't pay the cost of building the list.
+// The cost is not saved, but paid by the first lookup instead.
+func NewLazyList(fn func() []Rule) *List {
+	l := NewList()
+	l.lazy = fn
+	return l
+}
+
+// load adds the rules of a lazy list, the first time it is called.
+func (l *List) load() {
+	if l.lazy == nil {
+		return
 	}
+	l.once.Do(func() {
+		rules := l.lazy()
+
+		added := make([]*Rule, len(rules))
+		for i := range rules {
+			added[i] = &rules[i]
+		}
+		l.addRules(added)
+	})
 }
 
 // NewListFromString parses a string that represents a Public Suffix source
@@ -134,33 +307,184 @@ func (l *List) LoadFile(path string, options *ParserOption) ([]Rule, error) {
 // The exact position of the rule into the list is unpredictable.
 // The list may be optimized internally for lookups, therefore the algorithm
 // will decide the best position for the new rule.
+//
+// Rules with the same Value but a different Type, such as "foo.example" and "*.foo.example",
+// are kept side by side. A rule with the same Value and Type replaces the existing one.
+//
+// Each call copies the index of the list, hence Load, LoadString and LoadFile
+// are faster to add many rules at once.
 func (l *List) AddRule(r *Rule) error {
-	l.rules[r.Value] = r
+	l.load()
+	l.addRules([]*Rule{r})
 	return nil
 }
 
+// addRules adds the rules to a copy of the current ruleSet, and stores the copy.
+func (l *List) addRules(rules []*Rule) {
+	if len(rules) == 0 {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	cur := l.rules.Load()
+	set := &ruleSet{entries: maps.Clone(cur.entries), size: cur.size}
+	for _, r := range rules {
+		set.add(r)
+	}
+	l.rules.Store(set)
+}
+
+// add adds the rule to the set, which must not be stored yet.
+// The entries are copied before they are modified, because they are shared
+// with the ruleSet the set is copied from.
+func (s *ruleSet) add(r *Rule) {
+	var e entry
+	if cur, ok := s.entries[r.Value]; ok {
+		e = *cur
+	} else {
+		e.suffixException = s.hasSuffixException(r.Value)
+	}
+
+	if r.Type == ExceptionType && e.exception == nil {
+		for value, other := range s.entries {
+			if isSuffix(value, r.Value) && !other.suffixException {
+				c := *other
+				c.suffixException = true
+				s.entries[value] = &c
+			}
+		}
+	}
+
+	slot := e.slot(r.Type)
+	if *slot == nil {
+		s.size++
+	}
+	*slot = r
+	s.entries[r.Value] = &e
+}
+
+// hasSuffixException checks if the set contains an exception rule for a suffix of value.
+func (s *ruleSet) hasSuffixException(value string) bool {
+	for value != "" {
+		if i := strings.IndexByte(value, '.'); i < 0 {
+			value = ""
+		} else {
+			value = value[i+1:]
+		}
+		if e, ok := s.entries[value]; ok && e.exception != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// isSuffix checks if suffix is made of the right-most labels of value, value excluded.
+func isSuffix(value, suffix string) bool {
+	if suffix == "" {
+		return value != ""
+	}
+	return strings.HasSuffix(value, "."+suffix)
+}
+
 // Size returns the size of the list, which is the number of rules.
 func (l *List) Size() int {
-	return len(l.rules)
+	l.load()
+	return l.rules.Load().size
+}
+
+// Replace atomically replaces all the rules in the list with the rules in src.
+//
+// The list keeps no reference to src, so src can be modified or discarded afterwards.
+// Replace is meant to refresh a list in use, such as DefaultList, without interrupting
+// the goroutines that are performing lookups:
+//
+//	list, err := publicsuffix.NewListFromFile("public_suffix_list.dat", nil)
+//	if err != nil {
+//		return err
+//	}
+//	publicsuffix.DefaultList.Replace(list)
+func (l *List) Replace(src *List) {
+	if l == src {
+		return
+	}
+
+	// the rules of a lazy list that is not loaded yet are discarded anyway
+	l.once.Do(func() {})
+	src.load()
+
+	// the ruleSet is shared, because it's never modified once stored
+	l.mu.Lock()
+	l.rules.Store(src.rules.Load())
+	l.mu.Unlock()
+}
+
+// walk calls fn for each rule in the list, in no particular order.
+// fn must not modify the list.
+func (l *List) walk(fn func(*Rule)) {
+	l.load()
+
+	for _, e := range l.rules.Load().entries {
+		if e.normal != nil {
+			fn(e.normal)
+		}
+		if e.wildcard != nil {
+			fn(e.wildcard)
+		}
+		if e.exception != nil {
+			fn(e.exception)
+		}
+	}
+}
+
+// hasRule checks if the list contains a rule with the given type and value.
+func (l *List) hasRule(t int, value string) bool {
+	l.load()
+
+	e, ok := l.rules.Load().entries[value]
+	return ok && *e.slot(t) != nil
+}
+
+// parsedRule is a rule along with the line of the source it was parsed from.
+type parsedRule struct {
+	rule *Rule
+	line int
+	text string
 }
 
 func (l *List) parse(r io.Reader, options *ParserOption) ([]Rule, error) {
 	if options == nil {
 		options = DefaultParserOptions
 	}
+	l.load()
+
 	var rules []Rule
+	var added []*Rule
+	var parsed []parsedRule
+	var errs []error
 
 	scanner := bufio.NewScanner(r)
 	var section int // 1 == ICANN, 2 == PRIVATE
+	var lineNumber int
+
+	// The comment block that precedes a group of rules describes their owner.
+	// A comment that immediately follows a rule is a note within the same group,
+	// and it doesn't start a new block. A blank line ends the group.
+	var owner *Owner
+	var block []string
+	var blockLine int
+	var afterRule bool
 
 Scanning:
 	for scanner.Scan() {
+		lineNumber++
 		line := strings.TrimSpace(scanner.Text())
 		switch {
 
-		// skip blank lines
+		// skip blank lines, they end the current group of rules
 		case line == "":
-			break
+			owner, afterRule = nil, false
 
 		// include private domains or stop scanner
 		case strings.Contains(line, listTokenPrivateDomains):
@@ -168,10 +492,22 @@ Scanning:
 				break Scanning
 			}
 			section = 2
+			owner, block, afterRule = nil, nil, false
+
+		// section markers reset the owner
+		case strings.HasPrefix(line, listTokenComment+" ==="):
+			owner, block, afterRule = nil, nil, false
 
-		// skip comments
+		// collect the comments
 		case strings.HasPrefix(line, listTokenComment):
-			break
+			switch {
+			case afterRule:
+				// a note within the current group of rules
+			case block != nil && blockLine+len(block) == lineNumber:
+				block = append(block, line)
+			default:
+				block, blockLine = []string{line}, lineNumber
+			}
 
 		default:
 			var rule *Rule
@@ -180,44 +516,217 @@ Scanning:
 			if options.ASCIIEncoded {
 				rule, err = NewRule(line)
 			} else {
-				rule, err = NewRuleUnicode(line)
+				rule, err = newRuleUnicode(options.IDNA, line)
 			}
 			if err != nil {
-				return []Rule{}, err
+				err = &LineError{Line: lineNumber, Text: line, Err: err}
+				if !options.Strict {
+					l.addRules(added)
+					return []Rule{}, err
+				}
+				errs = append(errs, err)
+				continue
+			}
+
+			if block != nil {
+				owner, block = newOwner(block, blockLine), nil
 			}
+			afterRule = true
 
 			rule.Private = (section == 2)
-			l.AddRule(rule)
+			rule.Owner = owner
+			if options.Strict {
+				// in strict mode the rules are added only once the entire source is validated
+				parsed = append(parsed, parsedRule{rule: rule, line: lineNumber, text: line})
+			} else {
+				added = append(added, rule)
+			}
 			rules = append(rules, *rule)
 		}
 
 	}
+	if err := scanner.Err(); err != nil {
+		l.addRules(added)
+		return rules, err
+	}
+
+	if options.Strict {
+		errs = append(errs, l.validate(parsed)...)
+		if len(errs) > 0 {
+			return []Rule{}, errors.Join(errs...)
+		}
+		for _, p := range parsed {
+			added = append(added, p.rule)
+		}
+	}
+
+	// the rules are added at once, so that the index is copied only once
+	l.addRules(added)
+	return rules, nil
+}
+
+// validate checks the parsed rules for the problems reported by the strict mode.
+func (l *List) validate(parsed []parsedRule) []error {
+	var errs []error
+
+	type ruleKey struct {
+		typ   int
+		value string
+	}
+	seen := make(map[ruleKey]int, len(parsed))
+	for _, p := range parsed {
+		key := ruleKey{p.rule.Type, p.rule.Value}
+		if line, ok := seen[key]; ok {
+			errs = append(errs, &LineError{Line: p.line, Text: p.text, Err: fmt.Errorf("%w of line %d", ErrDuplicateRule, line)})
+			continue
+		}
+		seen[key] = p.line
+
+		switch {
+		case p.rule.Type == WildcardType && p.text != "*" && !strings.HasPrefix(p.text, "*."),
+			strings.Contains(p.rule.Value, "*"):
+			errs = append(errs, &LineError{Line: p.line, Text: p.text, Err: ErrMisplacedWildcard})
+		case p.text != "*" && slices.Contains(Labels(p.rule.Value), ""):
+			errs = append(errs, &LineError{Line: p.line, Text: p.text, Err: ErrEmptyLabel})
+		}
+	}
 
-	return rules, scanner.Err()
+	// an exception rule is meaningful only if there is a wildcard rule for its parent,
+	// either in the same source or already in the list
+	for _, p := range parsed {
+		if p.rule.Type != ExceptionType {
+			continue
+		}
+		parent := ""
+		if i := strings.IndexByte(p.rule.Value, '.'); i >= 0 {
+			parent = p.rule.Value[i+1:]
+		}
+		if _, ok := seen[ruleKey{WildcardType, parent}]; !ok && !l.hasRule(WildcardType, parent) {
+			errs = append(errs, &LineError{Line: p.line, Text: p.text, Err: ErrOrphanException})
+		}
+	}
+
+	return errs
+}
+
+// Owner returns the owner of the rule that applies to name, or nil if the rule
+// has no owner, such as when the list is not parsed from a commented source.
+//
+// For example, the owner of "blogspot.com" or "example.blogspot.com" is the organization
+// that submitted the "blogspot.com" rule to the private section of the list.
+func (l *List) Owner(name string) *Owner {
+	if r := l.Find(name, &FindOptions{}); r != nil {
+		return r.Owner
+	}
+	return nil
+}
+
+// PublicSuffix returns the public suffix of the name, whether the suffix is managed
+// by the ICANN rather than listed in the private section, and the rule that applies to name.
+// The rule is searched with the DefaultFindOptions.
+//
+// When no rule of the list applies and the DefaultRule is used, the suffix is the right-most label
+// and icann is false, as in golang.org/x/net/publicsuffix.
+// Like Find, the name is expected in lowercase and encoded in ASCII.
+//
+// Examples:
+//
+//	DefaultList.PublicSuffix("www.example.co.uk")
+//	// "co.uk", true, <*.uk>
+//	DefaultList.PublicSuffix("www.example.blogspot.com")
+//	// "blogspot.com", false, <blogspot.com>
+func (l *List) PublicSuffix(name string) (suffix string, icann bool, rule *Rule) {
+	rule = l.Find(name, DefaultFindOptions)
+	if rule == nil {
+		return "", false, nil
+	}
+
+	suffix = rule.Decompose(name)[1]
+	if suffix == "" {
+		// the name is itself a public suffix
+		suffix, _ = trimRootDot(name)
+	}
+	return suffix, rule.isICANN(), rule
+}
+
+// IsPublicSuffix reports whether the name is itself a public suffix,
+// such as "com", "co.uk" or, unless the options ignore the private domains, "blogspot.com".
+//
+// Like Find, the name is expected in lowercase and encoded in ASCII.
+func (l *List) IsPublicSuffix(name string, options *FindOptions) bool {
+	if name == "" {
+		return false
+	}
+	r := l.Find(name, options)
+	return r != nil && r.Decompose(name)[1] == ""
+}
+
+// IsRegistrableDomain reports whether the name is a registrable domain,
+// that is the public suffix plus one label, such as "example.co.uk".
+// The rule is searched with the DefaultFindOptions.
+//
+// Like Find, the name is expected in lowercase and encoded in ASCII.
+func (l *List) IsRegistrableDomain(name string) bool {
+	r := l.Find(name, DefaultFindOptions)
+	if r == nil {
+		return false
+	}
+	left := r.Decompose(name)[0]
+	return left != "" && strings.IndexByte(left, '.') < 0
 }
 
 // Find and returns the most appropriate rule for the domain name.
+//
+// The root dot of a fully-qualified name, as in "www.example.com.", is ignored.
 func (l *List) Find(name string, options *FindOptions) *Rule {
 	if options == nil {
 		options = DefaultFindOptions
 	}
 
+	l.load()
+	rules := l.rules.Load().entries
+
+	// Look up each suffix of the name, from the longest to the shortest, the empty one included.
+	// An exception rule takes priority, otherwise the rule with the most labels prevails.
+	// A wildcard rule counts one label more than its Value, hence it wins over
+	// a normal rule with the same Value.
+	name, _ = trimRootDot(name)
+
+	var match *Rule
 	part := name
 	for {
-		rule, ok := l.rules[part]
-
-		if ok && rule.Match(name) && !(options.IgnorePrivate && rule.Private) {
-			return rule
+		if e, ok := rules[part]; ok {
+			if r := e.exception; r != nil && !(options.IgnorePrivate && r.Private) {
+				return r
+			}
+			if match == nil {
+				// the wildcard requires one more label
+				if r := e.wildcard; r != nil && part != name && !(options.IgnorePrivate && r.Private) {
+					match = r
+				} else if r := e.normal; r != nil && !(options.IgnorePrivate && r.Private) {
+					match = r
+				}
+				// the shorter suffixes may only hold an exception
+				if match != nil && !e.suffixException {
+					return match
+				}
+			}
 		}
-
-		i := strings.IndexRune(part, '.')
-		if i < 0 {
-			return options.DefaultRule
+		if part == "" {
+			break
 		}
 
-		part = part[i+1:]
+		if i := strings.IndexByte(part, '.'); i < 0 {
+			part = ""
+		} else {
+			part = part[i+1:]
+		}
 	}
 
+	if match != nil {
+		return match
+	}
+	return options.DefaultRule
 }
 
 // NewRule parses the rule content, creates and returns a Rule.
@@ -248,14 +757,31 @@ func NewRule(content string) (*Rule, error) {
 
 // NewRuleUnicode is like NewRule, but expects the content to be encoded in Unicode (U-labels).
 func NewRuleUnicode(content string) (*Rule, error) {
-	var err error
+	return newRuleUnicode(nil, content)
+}
 
-	content, err = ToASCII(content)
-	if err != nil {
-		return nil, err
+// newRuleUnicode is like NewRuleUnicode, but converts the content with the IDNA profile.
+// The "!" and "*." prefixes are preserved, so that they are not validated by the profile.
+func newRuleUnicode(p *idna.Profile, content string) (*Rule, error) {
+	var prefix string
+	switch {
+	case strings.HasPrefix(content, "!"):
+		prefix, content = "!", content[1:]
+	case content == "*":
+		prefix, content = "*", ""
+	case strings.HasPrefix(content, "*."):
+		prefix, content = "*.", content[2:]
+	}
+
+	var err error
+	if content != "" {
+		content, err = toASCII(p, content)
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	return NewRule(content)
+	return NewRule(prefix + content)
 }
 
 // MustNewRule is like NewRule, but panics if the content cannot be parsed.
@@ -267,6 +793,24 @@ func MustNewRule(content string) *Rule {
 	return rule
 }
 
+// String returns the rule as it appears in the Public Suffix list,
+// such as "*.kawasaki.jp" or "!city.kawasaki.jp". The DefaultRule is "*".
+//
+// NewRule(r.String()) returns a rule equal to r, except for Private and Owner.
+func (r *Rule) String() string {
+	return formatRule(r.Type, r.Value)
+}
+
+// Unicode is like String, but returns the rule encoded in Unicode (U-labels).
+//
+// Examples:
+//
+//	MustNewRule("*.xn--85x722f.cn").Unicode()
+//	// *.食狮.cn
+func (r *Rule) Unicode() string {
+	return formatRule(r.Type, asUnicode(nil, r.Value))
+}
+
 // Match checks if the rule matches the name.
 //
 // A domain name is said to match a rule if and only if all of the following conditions are met:
@@ -276,8 +820,11 @@ func MustNewRule(content string) *Rule {
 //     and continuing for all labels in the rule, one finds that for every pair,
 //     either they are identical, or that the label from the rule is "*".
 //
+// The root dot of a fully-qualified name, as in "www.example.com.", is ignored.
+//
 // See https://publicsuffix.org/list/
 func (r *Rule) Match(name string) bool {
+	name, _ = trimRootDot(name)
 	left := strings.TrimSuffix(name, r.Value)
 
 	// the name contains as many labels than the rule
@@ -293,9 +840,19 @@ func (r *Rule) Match(name string) bool {
 	return left[len(left)-1:] == "."
 }
 
+// isICANN reports whether the rule is in the ICANN section of the list.
+// The default rule "*" is not, as in golang.org/x/net/publicsuffix.
+func (r *Rule) isICANN() bool {
+	return !r.Private && !(r.Value == "" && r.Type == WildcardType)
+}
+
 // Decompose takes a name as input and decomposes it into a tuple of <TRD+SLD, TLD>,
 // according to the rule definition and type.
+//
+// The root dot of a fully-qualified name, as in "www.example.com.", is ignored
+// and it's not included in the result.
 func (r *Rule) Decompose(name string) (result [2]string) {
+	name, _ = trimRootDot(name)
 	if r == DefaultRule {
 		i := strings.LastIndexByte(name, '.')
 		if i < 0 {
@@ -344,11 +901,22 @@ func Labels(name string) []string {
 }
 
 // DomainName represents a domain name.
+//
+// TLD, SLD and TRD are encoded in ASCII (A-labels).
+// Use UnicodeTLD, UnicodeSLD, UnicodeTRD and Unicode for the Unicode (U-labels) form.
 type DomainName struct {
 	TLD  string
 	SLD  string
 	TRD  string
 	Rule *Rule
+
+	// RootDot is true when the name is fully-qualified and the root dot is kept,
+	// as requested with FindOptions.KeepRootDot. String and Unicode then end with a dot.
+	RootDot bool
+
+	// IP is the address when the name is an IP address literal,
+	// as allowed with FindOptions.AllowIP. The other fields are then empty.
+	IP netip.Addr
 }
 
 // String joins the components of the domain name into a single string.
@@ -361,16 +929,48 @@ type DomainName struct {
 //	DomainName{"com", "example", "www"}.String()
 //	// www.example.com
 func (d *DomainName) String() string {
+	var s string
 	switch {
+	case d.IP.IsValid():
+		return d.IP.String()
 	case d.TLD == "":
 		return ""
 	case d.SLD == "":
-		return d.TLD
+		s = d.TLD
 	case d.TRD == "":
-		return d.SLD + "." + d.TLD
+		s = d.SLD + "." + d.TLD
 	default:
-		return d.TRD + "." + d.SLD + "." + d.TLD
+		s = d.TRD + "." + d.SLD + "." + d.TLD
 	}
+	if d.RootDot {
+		s += "."
+	}
+	return s
+}
+
+// UnicodeTLD returns the TLD encoded in Unicode (U-labels).
+func (d *DomainName) UnicodeTLD() string {
+	return asUnicode(nil, d.TLD)
+}
+
+// UnicodeSLD returns the SLD encoded in Unicode (U-labels).
+func (d *DomainName) UnicodeSLD() string {
+	return asUnicode(nil, d.SLD)
+}
+
+// UnicodeTRD returns the TRD encoded in Unicode (U-labels).
+func (d *DomainName) UnicodeTRD() string {
+	return asUnicode(nil, d.TRD)
+}
+
+// Unicode is like String, but returns the domain name encoded in Unicode (U-labels).
+//
+// Examples:
+//
+//	DomainName{"xn--55qx5d.cn", "xn--85x722f"}.Unicode()
+//	// 食狮.公司.cn
+func (d *DomainName) Unicode() string {
+	return asUnicode(nil, d.String())
 }
 
 // Domain extract and return the domain name from the input
@@ -424,13 +1024,35 @@ func DomainFromListWithOptions(l *List, name string, options *FindOptions) (stri
 	if err != nil {
 		return "", err
 	}
-	return dn.SLD + "." + dn.TLD, nil
+	if dn.IP.IsValid() {
+		return dn.IP.String(), nil
+	}
+
+	domain := dn.SLD + "." + dn.TLD
+	if dn.RootDot {
+		domain += "."
+	}
+	if options != nil && options.Unicode {
+		return asUnicode(options.IDNA, domain), nil
+	}
+	return domain, nil
 }
 
 // ParseFromListWithOptions decomposes the name into TLD, SLD, TRD
 // using the (Public Suffix) list passed as argument,
 // and returns the result as a DomainName
 //
+// The name can be encoded in Unicode (U-labels): it is converted to ASCII (A-labels)
+// with ToASCII, or the FindOptions IDNA profile, before the lookup, and the ideographic full stops U+3002, U+FF0E and U+FF61
+// are treated as dots. The components of the DomainName are always encoded in ASCII.
+//
+// If the name cannot be parsed, the error is a *ParseError that wraps one of
+// ErrBlankName, ErrLeadingDot, ErrInvalidIDN, ErrIsPublicSuffix or ErrNoRuleMatch,
+// or a *HostnameError when the FindOptions enable the hostname validation.
+//
+// An IPv4 or IPv6 address literal, optionally enclosed in brackets, fails with ErrIPAddress,
+// unless FindOptions.AllowIP is set: in this case the DomainName has only the IP set.
+//
 // Examples:
 //
 //	list := NewList()
@@ -442,25 +1064,33 @@ func DomainFromListWithOptions(l *List, name string, options *FindOptions) (stri
 //	publicsuffix.ParseFromListWithOptions(list, "www.example.co.uk")
 //	// &DomainName{"co.uk", "example"}
 func ParseFromListWithOptions(l *List, name string, options *FindOptions) (*DomainName, error) {
-	n, err := normalize(name)
+	if ip, ok := parseIP(name); ok {
+		if options == nil || !options.AllowIP {
+			return nil, &ParseError{Name: name, Err: ErrIPAddress}
+		}
+		return &DomainName{IP: ip}, nil
+	}
+
+	n, fqdn, err := normalize(name, options)
 	if err != nil {
 		return nil, err
 	}
 
 	r := l.Find(n, options)
 	if r == nil {
-		return nil, fmt.Errorf("no rule matching name %s", name)
+		return nil, &ParseError{Name: name, Err: ErrNoRuleMatch}
 	}
 
 	parts := r.Decompose(n)
 	left, tld := parts[0], parts[1]
 	if tld == "" {
-		return nil, fmt.Errorf("%s is a suffix", n)
+		return nil, &ParseError{Name: name, Rule: r, Err: ErrIsPublicSuffix}
 	}
 
 	dn := &DomainName{
-		Rule: r,
-		TLD:  tld,
+		Rule:    r,
+		TLD:     tld,
+		RootDot: fqdn && options != nil && options.KeepRootDot,
 	}
 	if i := strings.LastIndexByte(left, '.'); i < 0 {
 		dn.SLD = left
@@ -471,17 +1101,95 @@ func ParseFromListWithOptions(l *List, name string, options *FindOptions) (*Doma
 	return dn, nil
 }
 
-func normalize(name string) (string, error) {
-	ret := strings.ToLower(name)
+// idnaDots replaces the full stops that IDNA treats as label separators with a dot.
+var idnaDots = strings.NewReplacer("\u3002", ".", "\uff0e", ".", "\uff61", ".")
+
+// normalize returns the name in lowercase, encoded in ASCII and without the root dot,
+// and reports whether the name was fully-qualified.
+// The full-width characters are mapped to ASCII, as "Ｅｘａｍｐｌｅ．com" to "example.com".
+// A name that ends with more than one dot has an empty label.
+func normalize(name string, options *FindOptions) (string, bool, error) {
+	var profile *idna.Profile
+	var validation *HostnameValidation
+	if options != nil {
+		profile, validation = options.IDNA, options.Hostname
+	}
+
+	ret := name
+	ascii := isASCII(ret)
+	if !ascii {
+		ret = width.Fold.String(idnaDots.Replace(ret))
+		ascii = isASCII(ret)
+	}
+	ret = strings.ToLower(ret)
 
 	if ret == "" {
-		return "", fmt.Errorf("name is blank")
+		return "", false, &ParseError{Name: name, Err: ErrBlankName}
 	}
 	if ret[0] == '.' {
-		return "", fmt.Errorf("name %s starts with a dot", ret)
+		return "", false, &ParseError{Name: name, Err: ErrLeadingDot}
+	}
+
+	ret, fqdn := trimRootDot(ret)
+	if fqdn && ret[len(ret)-1] == '.' {
+		return "", false, &ParseError{Name: name, Err: ErrEmptyLabel}
 	}
 
-	return ret, nil
+	if !ascii || profile != nil {
+		a, err := toASCII(profile, ret)
+		if err != nil {
+			return "", false, &ParseError{Name: name, Err: fmt.Errorf("%w: %w", ErrInvalidIDN, err)}
+		}
+		ret = a
+	}
+
+	if validation != nil {
+		if err := validation.check(ret); err != nil {
+			return "", false, &ParseError{Name: name, Err: err}
+		}
+	}
+
+	return ret, fqdn, nil
+}
+
+// parseIP parses the name as an IPv4 or IPv6 address literal,
+// optionally enclosed in brackets as in a URL, and reports whether it is one.
+//
+// The name is checked as normalize sees it: the full-width characters are mapped to ASCII,
+// the ideographic full stops are treated as dots and the root dot is removed.
+func parseIP(name string) (netip.Addr, bool) {
+	if !isASCII(name) {
+		name = width.Fold.String(idnaDots.Replace(name))
+	}
+	name, _ = trimRootDot(name)
+	if len(name) > 2 && name[0] == '[' && name[len(name)-1] == ']' {
+		name = name[1 : len(name)-1]
+	}
+	// an IPv4 address ends with a digit, an IPv6 address contains a colon
+	if name == "" || (strings.IndexByte(name, ':') < 0 && (name[len(name)-1] < '0' || name[len(name)-1] > '9')) {
+		return netip.Addr{}, false
+	}
+
+	ip, err := netip.ParseAddr(name)
+	return ip, err == nil
+}
+
+// trimRootDot removes the root dot of a fully-qualified name, as in "www.example.com.",
+// and reports whether it was found. A name made of the root dot alone is returned unchanged.
+func trimRootDot(name string) (string, bool) {
+	if len(name) > 1 && name[len(name)-1] == '.' {
+		return name[:len(name)-1], true
+	}
+	return name, false
+}
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
 }
 
 // ToASCII is a wrapper for idna.ToASCII.
@@ -494,30 +1202,57 @@ func normalize(name string) (string, error) {
 // See golang/net@67957fd0b1, golang/net@f2499483f9, golang/net@78ebe5c8b6,
 // and weppos/publicsuffix-go#66.
 func ToASCII(s string) (string, error) {
-	// .example.com should be .example.com
-	// ..example.com should be ..example.com
-	if strings.HasPrefix(s, ".") {
-		dotIndex := 0
-		for i := 0; i < len(s); i++ {
-			if s[i] == '.' {
-				dotIndex = i
-			} else {
-				break
-			}
-		}
-		out, err := idna.ToASCII(s[dotIndex+1:])
-		out = s[:dotIndex+1] + out
-		return out, err
-	}
-
-	return idna.ToASCII(s)
+	return toASCII(nil, s)
 }
 
 // ToUnicode is a wrapper for idna.ToUnicode.
 //
 // See ToASCII for more details about why this wrapper exists.
 func ToUnicode(s string) (string, error) {
-	return idna.ToUnicode(s)
+	return toUnicode(nil, s)
+}
+
+// toASCII converts s to ASCII with the IDNA profile, or idna.Punycode if p is nil.
+func toASCII(p *idna.Profile, s string) (string, error) {
+	if p == nil {
+		p = idna.Punycode
+	}
+	return skipLeadingDots(p.ToASCII, s)
+}
+
+// toUnicode converts s to Unicode with the IDNA profile, or idna.Punycode if p is nil.
+func toUnicode(p *idna.Profile, s string) (string, error) {
+	if p == nil {
+		p = idna.Punycode
+	}
+	return skipLeadingDots(p.ToUnicode, s)
+}
+
+// asUnicode is like toUnicode, but it ignores the error.
+// The labels that cannot be converted are returned unchanged.
+func asUnicode(p *idna.Profile, s string) string {
+	u, _ := toUnicode(p, s)
+	return u
+}
+
+// skipLeadingDots applies the conversion to s without its leading dots,
+// and it adds them back to the result, regardless of the profile:
+// .example.com should be .example.com
+// ..example.com should be ..example.com
+func skipLeadingDots(convert func(string) (string, error), s string) (string, error) {
+	i := 0
+	for i < len(s) && s[i] == '.' {
+		i++
+	}
+	if i == 0 {
+		return convert(s)
+	}
+	if i == len(s) {
+		return s, nil
+	}
+
+	out, err := convert(s[i:])
+	return s[:i] + out, err
 }
 
 // CookieJarList implements the cookiejar.PublicSuffixList interface.
@@ -528,7 +1263,13 @@ type cookiejarList struct {
 }
 
 // PublicSuffix implements cookiejar.PublicSuffixList.
+//
+// An IP address literal is returned unchanged: it has no public suffix that can be shared,
+// and a cookie for an IP address applies only to the exact host, as in net/http/cookiejar.
 func (l cookiejarList) PublicSuffix(domain string) string {
+	if _, ok := parseIP(domain); ok {
+		return domain
+	}
 	rule := l.List.Find(domain, nil)
 	return rule.Decompose(domain)[1]
 }
@@ -1,12 +1,29 @@
 package publicsuffix
 
 import (
+	"errors"
+	"net/http"
+	"net/http/cookiejar"
+	"net/netip"
+	"net/url"
 	"reflect"
+	"strings"
+	"sync"
 	"testing"
 
+	"golang.org/x/net/idna"
 	xlib "golang.org/x/net/publicsuffix"
 )
 
+// listRules returns the rules in the list, in no particular order.
+func listRules(l *List) []*Rule {
+	var rules []*Rule
+	l.walk(func(r *Rule) {
+		rules = append(rules, r)
+	})
+	return rules
+}
+
 func TestNewListFromString(t *testing.T) {
 	src := `
 // This Source Code Form is subject to the terms of the Mozilla Public
@@ -35,10 +52,10 @@ blogspot.com
 
 	if want, got := 3, list.Size(); want != got {
 		t.Errorf("Parse returned a list with %v rules, want %v", got, want)
-		t.Fatalf("%v", list.rules)
+		t.Fatalf("%v", listRules(list))
 	}
 
-	rules := list.rules
+	rules := listRules(list)
 	var testRules []Rule
 
 	testRules = []Rule{}
@@ -81,7 +98,7 @@ xn--l1acc
 
 	if want, got := 2, list.Size(); want != got {
 		t.Errorf("Parse returned a list with %v rules, want %v", got, want)
-		t.Fatalf("%v", list.rules)
+		t.Fatalf("%v", listRules(list))
 	}
 
 	if rule := list.Find("hello.xn--d1alf", &FindOptions{DefaultRule: nil}); rule == nil {
@@ -112,7 +129,7 @@ xn--l1acc
 
 	if want, got := 2, list.Size(); want != got {
 		t.Errorf("Parse returned a list with %v rules, want %v", got, want)
-		t.Fatalf("%v", list.rules)
+		t.Fatalf("%v", listRules(list))
 	}
 
 	if rule := list.Find("hello.xn--d1alf", &FindOptions{DefaultRule: nil}); rule == nil {
@@ -126,6 +143,37 @@ xn--l1acc
 	}
 }
 
+func TestNewListFromString_IDNAProfile(t *testing.T) {
+	src := `
+*.食狮.cn
+!www.食狮.cn
+äbc.de
+`
+
+	list, err := NewListFromString(src, &ParserOption{IDNA: idna.Registration})
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+
+	want := []*Rule{
+		{Type: WildcardType, Value: "xn--85x722f.cn", Length: 3},
+		{Type: ExceptionType, Value: "www.xn--85x722f.cn", Length: 3},
+		{Type: NormalType, Value: "xn--bc-uia.de", Length: 2},
+	}
+	for _, rule := range want {
+		if !list.hasRule(rule.Type, rule.Value) {
+			t.Errorf("Parse returned a list without %v, got %v", rule, listRules(list))
+		}
+	}
+
+	// the registration profile rejects the underscore
+	_, err = NewListFromString("foo_bar.example\n", &ParserOption{IDNA: idna.Registration})
+	var lerr *LineError
+	if !errors.As(err, &lerr) || lerr.Line != 1 {
+		t.Errorf("Parse returned error %v, want a *LineError for line 1", err)
+	}
+}
+
 func TestNewListFromFile(t *testing.T) {
 	list, err := NewListFromFile("../fixtures/list-simple.txt", nil)
 	if err != nil {
@@ -134,10 +182,10 @@ func TestNewListFromFile(t *testing.T) {
 
 	if want, got := 3, list.Size(); want != got {
 		t.Errorf("Parse returned a list with %v rules, want %v", got, want)
-		t.Fatalf("%v", list.rules)
+		t.Fatalf("%v", listRules(list))
 	}
 
-	rules := list.rules
+	rules := listRules(list)
 	var testRules []Rule
 
 	testRules = []Rule{}
@@ -163,6 +211,110 @@ func TestNewListFromFile(t *testing.T) {
 	}
 }
 
+func TestNewListFromString_LineError(t *testing.T) {
+	src := `
+// comment
+com
+xn--b
+net
+xn--c
+`
+
+	_, err := NewListFromString(src, nil)
+	var lerr *LineError
+	if !errors.As(err, &lerr) {
+		t.Fatalf("Parse error %v is not a *LineError", err)
+	}
+	if lerr.Line != 4 || lerr.Text != "xn--b" {
+		t.Errorf("Parse error = %#v, want line %v and text %v", lerr, 4, "xn--b")
+	}
+}
+
+func TestNewListFromString_Strict(t *testing.T) {
+	src := `// ===BEGIN ICANN DOMAINS===
+com
+xn--b
+*.uk
+!parliament.uk
+!city.kobe.jp
+example..com
+.example.net
+foo.*.example
+*example.org
+*.
+com
+
+// ===BEGIN PRIVATE DOMAINS===
+blogspot.com
+`
+
+	list := NewList()
+	_, err := list.LoadString(src, &ParserOption{PrivateDomains: true, Strict: true})
+	if err == nil {
+		t.Fatalf("Parse should have returned error")
+	}
+	if want, got := 0, list.Size(); want != got {
+		t.Errorf("Parse in strict mode should not modify the list on error, got %v rules", got)
+	}
+
+	var errs []error
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		errs = joined.Unwrap()
+	}
+
+	want := []struct {
+		line int
+		err  error
+	}{
+		{3, nil},
+		{7, ErrEmptyLabel},
+		{8, ErrEmptyLabel},
+		{9, ErrMisplacedWildcard},
+		{10, ErrMisplacedWildcard},
+		{11, ErrEmptyLabel},
+		{12, ErrDuplicateRule},
+		{6, ErrOrphanException},
+	}
+	if len(errs) != len(want) {
+		t.Fatalf("Parse returned %v errors, want %v: %v", len(errs), len(want), err)
+	}
+	for i, w := range want {
+		var lerr *LineError
+		if !errors.As(errs[i], &lerr) {
+			t.Errorf("Parse error %v is not a *LineError", errs[i])
+			continue
+		}
+		if lerr.Line != w.line {
+			t.Errorf("Parse error %v line = %v, want %v", lerr, lerr.Line, w.line)
+		}
+		if w.err != nil && !errors.Is(lerr, w.err) {
+			t.Errorf("Parse error %v, want %v", lerr, w.err)
+		}
+	}
+}
+
+func TestNewListFromString_StrictValid(t *testing.T) {
+	src := `
+com
+!parliament.uk
+*.uk
+*
+`
+
+	list := NewList()
+	_ = list.AddRule(MustNewRule("*.kobe.jp"))
+	rules, err := list.LoadString(src+"!city.kobe.jp\n", &ParserOption{Strict: true})
+	if err != nil {
+		t.Fatalf("Parse returned an error: %v", err)
+	}
+	if want, got := 5, len(rules); want != got {
+		t.Errorf("Parse returned %v rules, want %v", got, want)
+	}
+	if want, got := 6, list.Size(); want != got {
+		t.Errorf("Parse returned a list with %v rules, want %v", got, want)
+	}
+}
+
 func TestListAddRule(t *testing.T) {
 	list := NewList()
 
@@ -175,13 +327,94 @@ func TestListAddRule(t *testing.T) {
 	if list.Size() != 1 {
 		t.Fatalf("List should have 1 rule, got %v", list.Size())
 	}
-	for _, got := range list.rules {
+	for _, got := range listRules(list) {
 		if !reflect.DeepEqual(rule, got) {
 			t.Fatalf("List[0] expected to be %v, got %v", rule, got)
 		}
 	}
 }
 
+func TestListReplace(t *testing.T) {
+	list, _ := NewListFromString("com\nnet", nil)
+	src, _ := NewListFromString("uk\n*.uk\n!parliament.uk", nil)
+
+	list.Replace(src)
+	if want, got := 3, list.Size(); want != got {
+		t.Fatalf("List should have %v rules, got %v", want, got)
+	}
+	if want, got := MustNewRule("!parliament.uk"), list.Find("parliament.uk", nil); !reflect.DeepEqual(want, got) {
+		t.Errorf("Find(%v) = %v, want %v", "parliament.uk", got, want)
+	}
+	if want, got := DefaultRule, list.Find("example.com", nil); want != got {
+		t.Errorf("Find(%v) = %v, want %v", "example.com", got, want)
+	}
+
+	// changes to the source must not affect the list
+	_ = src.AddRule(MustNewRule("com"))
+	if want, got := 3, list.Size(); want != got {
+		t.Fatalf("List should have %v rules, got %v", want, got)
+	}
+	if want, got := DefaultRule, list.Find("example.com", nil); want != got {
+		t.Errorf("Find(%v) = %v, want %v", "example.com", got, want)
+	}
+}
+
+func TestDefaultListReplace(t *testing.T) {
+	saved := NewList()
+	saved.Replace(DefaultList)
+	defer DefaultList.Replace(saved)
+
+	list, _ := NewListFromString("example", nil)
+	DefaultList.Replace(list)
+
+	if want, got := "example", CookieJarList.PublicSuffix("bar.foo.example"); want != got {
+		t.Errorf("CookieJarList.PublicSuffix(%v) = %v, want %v", "bar.foo.example", got, want)
+	}
+	if _, err := Domain("example"); !errors.Is(err, ErrIsPublicSuffix) {
+		t.Errorf("Domain(%v) error = %v, want %v", "example", err, ErrIsPublicSuffix)
+	}
+}
+
+// TestList_Concurrent is meant to be run with the race detector.
+func TestList_Concurrent(t *testing.T) {
+	list, _ := NewListFromString("com\n*.uk\n!parliament.uk", nil)
+	src, _ := NewListFromString("com\nnet", nil)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				_ = list.Find("www.example.co.uk", nil)
+				_, _ = ParseFromListWithOptions(list, "www.example.com", nil)
+				_ = list.Size()
+			}
+		}()
+	}
+
+	for j := 0; j < 100; j++ {
+		_ = list.AddRule(MustNewRule("co.uk"))
+		list.Replace(src)
+	}
+	wg.Wait()
+}
+
+func TestListFind_WhileWriting(t *testing.T) {
+	list, _ := NewListFromString("com\n*.uk", nil)
+
+	// the lookups don't wait for the changes in progress
+	list.mu.Lock()
+	defer list.mu.Unlock()
+
+	if want, got := MustNewRule("*.uk"), list.Find("www.example.co.uk", nil); !reflect.DeepEqual(want, got) {
+		t.Errorf("Find(%v) = %v, want %v", "www.example.co.uk", got, want)
+	}
+	if want, got := 2, list.Size(); want != got {
+		t.Errorf("Size() = %v, want %v", got, want)
+	}
+}
+
 type listFindTestCase struct {
 	input    string
 	expected *Rule
@@ -233,23 +466,33 @@ blogspot.com
 // ===END PRIVATE DOMAINS===
 	`
 
+	// the rules are owned by the comment block that precedes them
+	com := &Owner{Name: "com", Comment: "com", Line: 8}
+	uk := &Owner{Name: "uk", Comment: "uk", Line: 11}
+	jp := &Owner{Name: "jp", Comment: "jp", Line: 20}
+	google := &Owner{Name: "Google, Inc.", Comment: "Google, Inc.", Line: 40}
+	owned := func(rule *Rule, owner *Owner) *Rule {
+		rule.Owner = owner
+		return rule
+	}
+
 	// TODO(weppos): ability to set type to a rule.
-	p1 := MustNewRule("blogspot.com")
+	p1 := owned(MustNewRule("blogspot.com"), google)
 	p1.Private = true
 
 	testCases := []listFindTestCase{
 		// match standard
-		{"example.com", MustNewRule("com")},
-		{"foo.example.com", MustNewRule("com")},
+		{"example.com", owned(MustNewRule("com"), com)},
+		{"foo.example.com", owned(MustNewRule("com"), com)},
 
 		// match wildcard
-		{"example.uk", MustNewRule("*.uk")},
-		{"example.co.uk", MustNewRule("*.uk")},
-		{"foo.example.co.uk", MustNewRule("*.uk")},
+		{"example.uk", owned(MustNewRule("*.uk"), uk)},
+		{"example.co.uk", owned(MustNewRule("*.uk"), uk)},
+		{"foo.example.co.uk", owned(MustNewRule("*.uk"), uk)},
 
 		// match exception
-		{"british-library.uk", MustNewRule("!british-library.uk")},
-		{"foo.british-library.uk", MustNewRule("!british-library.uk")},
+		{"british-library.uk", owned(MustNewRule("!british-library.uk"), uk)},
+		{"foo.british-library.uk", owned(MustNewRule("!british-library.uk"), uk)},
 
 		// match default rule
 		{"test", DefaultRule},
@@ -261,7 +504,7 @@ blogspot.com
 		{"foo.blogspot.com", p1},
 
 		// input is wildcard rule
-		{"kobe.jp", MustNewRule("jp")},
+		{"kobe.jp", owned(MustNewRule("jp"), jp)},
 	}
 
 	list, err := NewListFromString(src, nil)
@@ -276,6 +519,261 @@ blogspot.com
 	}
 }
 
+func TestListOwner(t *testing.T) {
+	src := `// ===BEGIN ICANN DOMAINS===
+
+// jp : https://en.wikipedia.org/wiki/.jp
+// Submitted by registry <info@jprs.jp>
+jp
+// jp organizational type names
+ac.jp
+*.kobe.jp
+
+uk
+// ===END ICANN DOMAINS===
+// ===BEGIN PRIVATE DOMAINS===
+// (Note: these are in alphabetical order by company name)
+
+// Amazon : https://www.amazon.com/
+// Submitted by AWS Security <psl-maintainers@amazon.com>
+execute-api.cn-north-1.amazonaws.com.cn
+
+// Google, Inc.
+blogspot.com
+// ===END PRIVATE DOMAINS===
+`
+
+	list, err := NewListFromString(src, nil)
+	if err != nil {
+		t.Fatalf("Parse returned an error: %v", err)
+	}
+
+	jp := &Owner{Name: "jp", URL: "https://en.wikipedia.org/wiki/.jp", Comment: "jp : https://en.wikipedia.org/wiki/.jp\nSubmitted by registry <info@jprs.jp>", Line: 3}
+	amazon := &Owner{Name: "Amazon", URL: "https://www.amazon.com/", Comment: "Amazon : https://www.amazon.com/\nSubmitted by AWS Security <psl-maintainers@amazon.com>", Line: 15}
+	google := &Owner{Name: "Google, Inc.", Comment: "Google, Inc.", Line: 19}
+
+	testCases := []struct {
+		input string
+		want  *Owner
+	}{
+		{"jp", jp},
+		{"example.ac.jp", jp},
+		{"foo.kobe.jp", jp},
+		// the group of rules ends with a blank line
+		{"example.uk", nil},
+		{"example.execute-api.cn-north-1.amazonaws.com.cn", amazon},
+		{"blogspot.com", google},
+		{"example.blogspot.com", google},
+		{"example.test", nil},
+	}
+
+	for _, testCase := range testCases {
+		if want, got := testCase.want, list.Owner(testCase.input); !reflect.DeepEqual(want, got) {
+			t.Errorf("Owner(%v) = %+v, want %+v", testCase.input, got, want)
+		}
+	}
+
+	// the rules of a group share the same owner
+	if want, got := list.Find("jp", nil).Owner, list.Find("ac.jp", nil).Owner; want != got {
+		t.Errorf("Owner(%v) = %p, want %p", "ac.jp", got, want)
+	}
+}
+
+func TestDefaultListOwner(t *testing.T) {
+	if owner := DefaultList.Owner("blogspot.com"); owner == nil || owner.Name == "" {
+		t.Fatalf("DefaultList.Owner(blogspot.com) = %+v, want an owner with a name", owner)
+	}
+
+	dn, err := Parse("www.example.blogspot.com")
+	if err != nil {
+		t.Fatalf("Parse returned an error: %v", err)
+	}
+	if want, got := DefaultList.Owner("blogspot.com"), dn.Rule.Owner; got != want {
+		t.Errorf("Parse(www.example.blogspot.com).Rule.Owner = %+v, want %+v", got, want)
+	}
+}
+
+func TestListPublicSuffix(t *testing.T) {
+	testCases := []struct {
+		input  string
+		suffix string
+		icann  bool
+		rule   string
+	}{
+		{"www.example.com", "com", true, "com"},
+		{"com", "com", true, "com"},
+		{"www.example.co.uk", "co.uk", true, "co.uk"},
+		{"co.uk", "co.uk", true, "co.uk"},
+		{"www.parliament.uk", "uk", true, "uk"},
+		{"www.city.kobe.jp", "kobe.jp", true, "city.kobe.jp"},
+		{"www.example.kobe.jp", "example.kobe.jp", true, "kobe.jp"},
+		{"www.example.blogspot.com", "blogspot.com", false, "blogspot.com"},
+		{"www.example.com.", "com", true, "com"},
+		{"com.", "com", true, "com"},
+		// not listed
+		{"www.example.test", "test", false, ""},
+		{"test", "test", false, ""},
+	}
+
+	for _, testCase := range testCases {
+		suffix, icann, rule := DefaultList.PublicSuffix(testCase.input)
+		if suffix != testCase.suffix || icann != testCase.icann {
+			t.Errorf("PublicSuffix(%v) = %v, %v, want %v, %v", testCase.input, suffix, icann, testCase.suffix, testCase.icann)
+		}
+		if rule == nil || rule.Value != testCase.rule {
+			t.Errorf("PublicSuffix(%v) rule = %v, want %v", testCase.input, rule, testCase.rule)
+		}
+	}
+}
+
+func TestListIsPublicSuffix(t *testing.T) {
+	testCases := []struct {
+		input   string
+		options *FindOptions
+		want    bool
+	}{
+		{"com", nil, true},
+		{"co.uk", nil, true},
+		{"uk", nil, true},
+		{"parliament.uk", nil, false},
+		{"example.co.uk", nil, false},
+		{"www.example.co.uk", nil, false},
+		{"blogspot.com", nil, true},
+		{"blogspot.com", &FindOptions{IgnorePrivate: true, DefaultRule: DefaultRule}, false},
+		{"co.uk.", nil, true},
+		// not listed
+		{"test", nil, true},
+		{"test", &FindOptions{DefaultRule: nil}, false},
+		{"", nil, false},
+	}
+
+	for _, testCase := range testCases {
+		if got := DefaultList.IsPublicSuffix(testCase.input, testCase.options); got != testCase.want {
+			t.Errorf("IsPublicSuffix(%v, %v) = %v, want %v", testCase.input, testCase.options, got, testCase.want)
+		}
+	}
+}
+
+func TestListIsRegistrableDomain(t *testing.T) {
+	testCases := map[string]bool{
+		"example.com":          true,
+		"www.example.com":      false,
+		"com":                  false,
+		"example.co.uk":        true,
+		"co.uk":                false,
+		"parliament.uk":        true,
+		"www.parliament.uk":    false,
+		"example.blogspot.com": true,
+		"blogspot.com":         false,
+		"example.com.":         true,
+		"city.kobe.jp":         true,
+		"example.kobe.jp":      false,
+		"www.example.kobe.jp":  true,
+		"example.test":         true,
+		"www.example.test":     false,
+		"":                     false,
+	}
+
+	for input, want := range testCases {
+		if got := DefaultList.IsRegistrableDomain(input); got != want {
+			t.Errorf("IsRegistrableDomain(%v) = %v, want %v", input, got, want)
+		}
+	}
+}
+
+func TestListIsPublicSuffix_NoAllocations(t *testing.T) {
+	DefaultList.Size() // load the list
+	allocs := testing.AllocsPerRun(100, func() {
+		DefaultList.IsPublicSuffix("www.example.com", nil)
+		DefaultList.IsRegistrableDomain("example.com")
+		DefaultList.PublicSuffix("www.example.com")
+	})
+	if allocs != 0 {
+		t.Errorf("IsPublicSuffix, IsRegistrableDomain and PublicSuffix allocated %v times, want 0", allocs)
+	}
+}
+
+func TestListAddRule_SameValue(t *testing.T) {
+	list := NewList()
+
+	normal := MustNewRule("foo.example")
+	wildcard := MustNewRule("*.foo.example")
+	exception := MustNewRule("!foo.example")
+	for _, rule := range []*Rule{normal, wildcard, exception} {
+		_ = list.AddRule(rule)
+	}
+	if want, got := 3, list.Size(); want != got {
+		t.Fatalf("List should have %v rules, got %v", want, got)
+	}
+
+	// same value and type replaces the existing rule
+	replacement := MustNewRule("*.foo.example")
+	replacement.Private = true
+	_ = list.AddRule(replacement)
+	if want, got := 3, list.Size(); want != got {
+		t.Fatalf("List should have %v rules, got %v", want, got)
+	}
+	for _, got := range listRules(list) {
+		if got == wildcard {
+			t.Fatalf("List expected to replace %v", wildcard)
+		}
+	}
+}
+
+func TestListFind_SameValue(t *testing.T) {
+	testCases := []struct {
+		src   string
+		cases []listFindTestCase
+	}{
+		// normal and wildcard rule with the same value
+		{"example\nfoo.example\n*.foo.example", []listFindTestCase{
+			{"foo.example", MustNewRule("foo.example")},
+			{"bar.foo.example", MustNewRule("*.foo.example")},
+			{"baz.bar.foo.example", MustNewRule("*.foo.example")},
+		}},
+		// the loading order doesn't matter
+		{"example\n*.foo.example\nfoo.example", []listFindTestCase{
+			{"foo.example", MustNewRule("foo.example")},
+			{"bar.foo.example", MustNewRule("*.foo.example")},
+		}},
+		// exception rule takes priority over a longer rule
+		{"jp\n*.kobe.jp\n!city.kobe.jp\nwww.city.kobe.jp", []listFindTestCase{
+			{"kobe.jp", MustNewRule("jp")},
+			{"c.kobe.jp", MustNewRule("*.kobe.jp")},
+			{"city.kobe.jp", MustNewRule("!city.kobe.jp")},
+			{"www.city.kobe.jp", MustNewRule("!city.kobe.jp")},
+			{"foo.www.city.kobe.jp", MustNewRule("!city.kobe.jp")},
+		}},
+		// the loading order doesn't matter
+		{"www.city.kobe.jp\n!city.kobe.jp\n*.kobe.jp\njp", []listFindTestCase{
+			{"c.kobe.jp", MustNewRule("*.kobe.jp")},
+			{"www.city.kobe.jp", MustNewRule("!city.kobe.jp")},
+			{"foo.www.city.kobe.jp", MustNewRule("!city.kobe.jp")},
+		}},
+		// wildcard rule wins over a normal rule with the same value,
+		// and a normal rule wins over a wildcard rule with the same length
+		{"uk\n*.uk\nco.uk", []listFindTestCase{
+			{"uk", MustNewRule("uk")},
+			{"example.uk", MustNewRule("*.uk")},
+			{"co.uk", MustNewRule("co.uk")},
+			{"example.co.uk", MustNewRule("co.uk")},
+		}},
+	}
+
+	for _, testCase := range testCases {
+		list, err := NewListFromString(testCase.src, nil)
+		if err != nil {
+			t.Fatalf("Unable to parse list: %v", err)
+		}
+
+		for _, c := range testCase.cases {
+			if want, got := c.expected, list.Find(c.input, nil); !reflect.DeepEqual(want, got) {
+				t.Errorf("Find(%v) = %v, want %v", c.input, got, want)
+			}
+		}
+	}
+}
+
 func TestNewRule_Normal(t *testing.T) {
 	rule := MustNewRule("com")
 	want := &Rule{Type: NormalType, Value: "com", Length: 1}
@@ -335,6 +833,50 @@ func TestNewRuleUnicode_FromUnicode(t *testing.T) {
 	}
 }
 
+func TestRuleString(t *testing.T) {
+	testCases := []struct {
+		rule    *Rule
+		str     string
+		unicode string
+	}{
+		{MustNewRule("com"), "com", "com"},
+		{MustNewRule("*.kawasaki.jp"), "*.kawasaki.jp", "*.kawasaki.jp"},
+		{MustNewRule("!city.kawasaki.jp"), "!city.kawasaki.jp", "!city.kawasaki.jp"},
+		{MustNewRule("*.xn--85x722f.cn"), "*.xn--85x722f.cn", "*.食狮.cn"},
+		{MustNewRule("!xn--l1acc.xn--p1ai"), "!xn--l1acc.xn--p1ai", "!мон.рф"},
+		{DefaultRule, "*", "*"},
+	}
+
+	for _, testCase := range testCases {
+		if got := testCase.rule.String(); got != testCase.str {
+			t.Errorf("Rule.String() = %v, want %v", got, testCase.str)
+		}
+		if got := testCase.rule.Unicode(); got != testCase.unicode {
+			t.Errorf("Rule.Unicode() = %v, want %v", got, testCase.unicode)
+		}
+	}
+}
+
+func TestRuleString_RoundTrip(t *testing.T) {
+	for _, r := range DefaultRules() {
+		got, err := NewRule(r.String())
+		if err != nil {
+			t.Fatalf("NewRule(%v) returned error: %v", r.String(), err)
+		}
+		if got.Type != r.Type || got.Value != r.Value || got.Length != r.Length {
+			t.Errorf("NewRule(%v) = %+v, want %+v", r.String(), got, r)
+		}
+
+		got, err = NewRuleUnicode(r.Unicode())
+		if err != nil {
+			t.Fatalf("NewRuleUnicode(%v) returned error: %v", r.Unicode(), err)
+		}
+		if got.Type != r.Type || got.Value != r.Value || got.Length != r.Length {
+			t.Errorf("NewRuleUnicode(%v) = %+v, want %+v", r.Unicode(), got, r)
+		}
+	}
+}
+
 type ruleMatchTestCase struct {
 	rule     *Rule
 	input    string
@@ -373,6 +915,13 @@ func TestRuleMatch(t *testing.T) {
 		{MustNewRule("le.it"), "example.it", false},
 		{MustNewRule("le.it"), "le.it", true},
 		{MustNewRule("le.it"), "foo.le.it", true},
+
+		// fully-qualified names
+		{MustNewRule("uk"), "example.co.uk.", true},
+		{MustNewRule("co.uk"), "co.uk.", true},
+		{MustNewRule("*.com"), "com.", false},
+		{MustNewRule("*.com"), "example.com.", true},
+		{MustNewRule("gk"), "example.uk.", false},
 	}
 
 	for _, testCase := range testCases {
@@ -402,6 +951,12 @@ func TestRuleDecompose(t *testing.T) {
 		{MustNewRule("*.com"), "example.com", [2]string{"", ""}},
 		{MustNewRule("*.com"), "foo.example.com", [2]string{"foo", "example.com"}},
 		{MustNewRule("*.com"), "bar.foo.example.com", [2]string{"bar.foo", "example.com"}},
+
+		// fully-qualified names
+		{MustNewRule("com"), "foo.example.com.", [2]string{"foo.example", "com"}},
+		{MustNewRule("*.com"), "foo.example.com.", [2]string{"foo", "example.com"}},
+		{MustNewRule("!british-library.uk"), "british-library.uk.", [2]string{"british-library", "uk"}},
+		{DefaultRule, "foo.example.test.", [2]string{"foo.example", "test"}},
 	}
 
 	for _, testCase := range testCases {
@@ -478,6 +1033,159 @@ func TestParseFromListWithOptions_RuleNotFoundDefaultRule(t *testing.T) {
 	}
 }
 
+func TestParseFromListWithOptions_Unicode(t *testing.T) {
+	list := NewList()
+	rule := MustNewRule("xn--55qx5d.cn")
+	_ = list.AddRule(rule)
+
+	for _, input := range []string{"www.食狮.公司.cn", "WWW.食狮。公司．CN", "www.xn--85x722f.xn--55qx5d.cn"} {
+		got, err := ParseFromListWithOptions(list, input, nil)
+		if err != nil {
+			t.Fatalf("ParseFromListWithOptions(%v) error: %v", input, err)
+		}
+
+		want := &DomainName{TLD: "xn--55qx5d.cn", SLD: "xn--85x722f", TRD: "www", Rule: rule}
+		if !reflect.DeepEqual(want, got) {
+			t.Errorf("ParseFromListWithOptions(%v) = %v, want %v", input, got, want)
+		}
+		if want, got := "公司.cn", got.UnicodeTLD(); want != got {
+			t.Errorf("ParseFromListWithOptions(%v).UnicodeTLD() = %v, want %v", input, got, want)
+		}
+		if want, got := "食狮", got.UnicodeSLD(); want != got {
+			t.Errorf("ParseFromListWithOptions(%v).UnicodeSLD() = %v, want %v", input, got, want)
+		}
+		if want, got := "www", got.UnicodeTRD(); want != got {
+			t.Errorf("ParseFromListWithOptions(%v).UnicodeTRD() = %v, want %v", input, got, want)
+		}
+		if want, got := "www.食狮.公司.cn", got.Unicode(); want != got {
+			t.Errorf("ParseFromListWithOptions(%v).Unicode() = %v, want %v", input, got, want)
+		}
+	}
+}
+
+func TestParseFromListWithOptions_Errors(t *testing.T) {
+	list := NewList()
+	rule := MustNewRule("com")
+	_ = list.AddRule(rule)
+
+	testCases := []struct {
+		input   string
+		options *FindOptions
+		err     error
+		name    string
+		rule    *Rule
+		message string
+	}{
+		{"", nil, ErrBlankName, "", nil, "name is blank"},
+		{".example.com", nil, ErrLeadingDot, ".example.com", nil, "name .example.com starts with a dot"},
+		{".Example.com", nil, ErrLeadingDot, ".Example.com", nil, "name .Example.com starts with a dot"},
+		{"com", nil, ErrIsPublicSuffix, "com", rule, "com is a suffix"},
+		{"COM.", nil, ErrIsPublicSuffix, "COM.", rule, "COM. is a suffix"},
+		{"example.test", &FindOptions{}, ErrNoRuleMatch, "example.test", nil, "no rule matching name example.test"},
+		{"xn--ü.com", nil, ErrInvalidIDN, "xn--ü.com", nil, `name xn--ü.com: name is not a valid internationalized domain name: idna: invalid label "ü"`},
+	}
+
+	for _, testCase := range testCases {
+		_, err := ParseFromListWithOptions(list, testCase.input, testCase.options)
+		if !errors.Is(err, testCase.err) {
+			t.Errorf("ParseFromListWithOptions(%v) error = %v, want %v", testCase.input, err, testCase.err)
+			continue
+		}
+		if want, got := testCase.message, err.Error(); want != got {
+			t.Errorf("ParseFromListWithOptions(%v) error message = %v, want %v", testCase.input, got, want)
+		}
+
+		var perr *ParseError
+		if !errors.As(err, &perr) {
+			t.Errorf("ParseFromListWithOptions(%v) error %T is not a *ParseError", testCase.input, err)
+			continue
+		}
+		if perr.Name != testCase.name || perr.Rule != testCase.rule {
+			t.Errorf("ParseFromListWithOptions(%v) error = %#v, want name %v and rule %v", testCase.input, perr, testCase.name, testCase.rule)
+		}
+	}
+}
+
+func TestParseFromListWithOptions_IP(t *testing.T) {
+	list := NewList()
+	_ = list.AddRule(MustNewRule("com"))
+
+	testCases := []struct {
+		input string
+		ip    string
+	}{
+		{"192.168.0.1", "192.168.0.1"},
+		{"::1", "::1"},
+		{"[2001:DB8::1]", "2001:db8::1"},
+		{"fe80::1%eth0", "fe80::1%eth0"},
+		{"::ffff:192.168.0.1", "::ffff:192.168.0.1"},
+		// a fully-qualified name, and full-width characters
+		{"192.168.0.1.", "192.168.0.1"},
+		{"[::1].", "::1"},
+		{"１９２.168.0.1", "192.168.0.1"},
+		{"１９２。１６８．０｡１", "192.168.0.1"},
+		{"［２００１：ＤＢ８::1］", "2001:db8::1"},
+	}
+
+	for _, testCase := range testCases {
+		_, err := ParseFromListWithOptions(list, testCase.input, &FindOptions{DefaultRule: DefaultRule})
+		if !errors.Is(err, ErrIPAddress) {
+			t.Errorf("ParseFromListWithOptions(%v) error = %v, want %v", testCase.input, err, ErrIPAddress)
+		}
+
+		options := &FindOptions{DefaultRule: DefaultRule, AllowIP: true}
+		got, err := ParseFromListWithOptions(list, testCase.input, options)
+		if err != nil {
+			t.Errorf("ParseFromListWithOptions(%v) returned error: %v", testCase.input, err)
+			continue
+		}
+		want := &DomainName{IP: netip.MustParseAddr(testCase.ip)}
+		if !reflect.DeepEqual(want, got) {
+			t.Errorf("ParseFromListWithOptions(%v) = %#v, want %#v", testCase.input, got, want)
+		}
+		if got.String() != testCase.ip {
+			t.Errorf("ParseFromListWithOptions(%v).String() = %v, want %v", testCase.input, got, testCase.ip)
+		}
+
+		domain, err := DomainFromListWithOptions(list, testCase.input, options)
+		if err != nil || domain != testCase.ip {
+			t.Errorf("DomainFromListWithOptions(%v) = %v, %v, want %v", testCase.input, domain, err, testCase.ip)
+		}
+
+		if _, err := Domain(testCase.input); !errors.Is(err, ErrIPAddress) {
+			t.Errorf("Domain(%v) error = %v, want %v", testCase.input, err, ErrIPAddress)
+		}
+	}
+
+	// names that look like an IP address
+	for _, input := range []string{"1.2.3.4.com", "example.123", "1.2.3.4..", "[example.com]"} {
+		if _, err := ParseFromListWithOptions(list, input, &FindOptions{DefaultRule: DefaultRule}); errors.Is(err, ErrIPAddress) {
+			t.Errorf("ParseFromListWithOptions(%v) error = %v, want not %v", input, err, ErrIPAddress)
+		}
+	}
+}
+
+func TestCookieJarList_IP(t *testing.T) {
+	jar, err := cookiejar.New(&cookiejar.Options{PublicSuffixList: CookieJarList})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, host := range []string{"192.168.0.1", "[::1]"} {
+		u := &url.URL{Scheme: "http", Host: host, Path: "/"}
+		jar.SetCookies(u, []*http.Cookie{{Name: "session", Value: "1"}})
+		if cookies := jar.Cookies(u); len(cookies) != 1 {
+			t.Errorf("Cookies(%v) = %v, want 1 cookie", u, cookies)
+		}
+	}
+
+	for _, host := range []string{"192.168.0.1", "192.168.0.1.", "１９２.168.0.1", "::1"} {
+		if got := CookieJarList.PublicSuffix(host); got != host {
+			t.Errorf("CookieJarList.PublicSuffix(%v) = %v, want %v", host, got, host)
+		}
+	}
+}
+
 func TestToASCII(t *testing.T) {
 	testCases := []string{
 		"example.com",
@@ -496,6 +1204,70 @@ func TestToASCII(t *testing.T) {
 	}
 }
 
+func TestToASCII_Profiles(t *testing.T) {
+	profiles := map[string]*idna.Profile{
+		"Punycode":     idna.Punycode,
+		"Lookup":       idna.Lookup,
+		"Display":      idna.Display,
+		"Registration": idna.Registration,
+	}
+	testCases := []struct {
+		input, ascii string
+	}{
+		{"example.com", "example.com"},
+		{".example.com", ".example.com"},
+		{"..example.com", "..example.com"},
+		{"..食狮.公司.cn", "..xn--85x722f.xn--55qx5d.cn"},
+		{"..", ".."},
+	}
+
+	for name, profile := range profiles {
+		for _, testCase := range testCases {
+			output, err := toASCII(profile, testCase.input)
+			if err != nil {
+				t.Errorf("toASCII(%s, %s) returned error: %v", name, testCase.input, err)
+			}
+			if output != testCase.ascii {
+				t.Errorf("toASCII(%s, %s) = %s, want %s", name, testCase.input, output, testCase.ascii)
+			}
+		}
+	}
+}
+
+func TestParseFromListWithOptions_IDNAProfile(t *testing.T) {
+	list := NewList()
+	_ = list.AddRule(MustNewRule("com"))
+	_ = list.AddRule(MustNewRule("de"))
+
+	transitional := idna.New(idna.MapForLookup(), idna.Transitional(true))
+
+	testCases := []struct {
+		input   string
+		profile *idna.Profile
+		domain  string
+		err     error
+	}{
+		{"faß.de", nil, "xn--fa-hia.de", nil},
+		{"faß.de", idna.Lookup, "xn--fa-hia.de", nil},
+		{"faß.de", transitional, "fass.de", nil},
+		{"www.foo_bar.com", nil, "foo_bar.com", nil},
+		{"www.foo_bar.com", idna.Lookup, "", ErrInvalidIDN},
+		{"www.xn--b.com", nil, "xn--b.com", nil},
+		{"www.xn--b.com", idna.Registration, "", ErrInvalidIDN},
+	}
+
+	for _, testCase := range testCases {
+		got, err := DomainFromListWithOptions(list, testCase.input, &FindOptions{IDNA: testCase.profile})
+		if !errors.Is(err, testCase.err) {
+			t.Errorf("DomainFromListWithOptions(%v) error = %v, want %v", testCase.input, err, testCase.err)
+			continue
+		}
+		if got != testCase.domain {
+			t.Errorf("DomainFromListWithOptions(%v) = %v, want %v", testCase.input, got, testCase.domain)
+		}
+	}
+}
+
 func TestCookieJarList(t *testing.T) {
 	testCases := map[string]string{
 		"example.com":              "com",
@@ -508,6 +1280,11 @@ func TestCookieJarList(t *testing.T) {
 		"www.parliament.uk":        "uk",
 		// not listed
 		"www.example.test": "test",
+		// fully-qualified
+		"www.example.co.uk.": "co.uk",
+		// IP addresses
+		"192.168.0.1": "192.168.0.1",
+		"::1":         "::1",
 	}
 
 	for input, suffix := range testCases {
@@ -549,3 +1326,78 @@ func BenchmarkDomain(b *testing.B) {
 func BenchmarkXNet(b *testing.B) {
 	benchmarkDomain(b, xlib.EffectiveTLDPlusOne)
 }
+
+// mapList is the map-based lookup used by List before the rules with the same Value
+// were kept side by side. It is kept as a baseline for the Find benchmarks.
+type mapList struct {
+	rules map[string]*Rule
+}
+
+func newMapList(l *List) *mapList {
+	ml := &mapList{rules: map[string]*Rule{}}
+	l.walk(func(r *Rule) {
+		ml.rules[r.Value] = r
+	})
+	return ml
+}
+
+func (l *mapList) Find(name string, options *FindOptions) *Rule {
+	if options == nil {
+		options = DefaultFindOptions
+	}
+
+	part := name
+	for {
+		rule, ok := l.rules[part]
+
+		if ok && rule.Match(name) && !(options.IgnorePrivate && rule.Private) {
+			return rule
+		}
+
+		i := strings.IndexRune(part, '.')
+		if i < 0 {
+			return options.DefaultRule
+		}
+
+		part = part[i+1:]
+	}
+}
+
+func TestListFind_NoAllocations(t *testing.T) {
+	allocs := testing.AllocsPerRun(100, func() {
+		for input := range benchmarkTestCases {
+			DefaultList.Find(input, nil)
+		}
+	})
+	if allocs != 0 {
+		t.Errorf("Find allocated %v times, want 0", allocs)
+	}
+}
+
+func TestListFind_MatchesMapList(t *testing.T) {
+	ml := newMapList(DefaultList)
+	for input := range benchmarkTestCases {
+		if want, got := ml.Find(input, nil), DefaultList.Find(input, nil); want != got {
+			t.Errorf("Find(%v) = %v, want %v", input, got, want)
+		}
+	}
+}
+
+func benchmarkFind(b *testing.B, findFunc func(string, *FindOptions) *Rule) {
+	b.ReportAllocs()
+	var got *Rule
+	for i := 0; i < b.N; i++ {
+		for input := range benchmarkTestCases {
+			got = findFunc(input, nil)
+		}
+	}
+	_ = got
+}
+
+func BenchmarkListFind(b *testing.B) {
+	benchmarkFind(b, DefaultList.Find)
+}
+
+func BenchmarkListFind_Map(b *testing.B) {
+	benchmarkFind(b, newMapList(DefaultList).Find)
+}
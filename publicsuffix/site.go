@@ -0,0 +1,87 @@
+package publicsuffix
+
+import (
+	"errors"
+	"net/url"
+)
+
+// SameSite reports whether the hosts a and b belong to the same site
+// using the default (Public Suffix) List.
+//
+// Examples:
+//
+//	publicsuffix.SameSite("www.example.co.uk", "static.example.co.uk")
+//	// true
+//	publicsuffix.SameSite("foo.blogspot.com", "bar.blogspot.com")
+//	// false
+func SameSite(a, b string) bool {
+	return SameSiteFromListWithOptions(DefaultList, a, b, DefaultFindOptions)
+}
+
+// SameSiteFromListWithOptions is like SameSite,
+// but uses the (Public Suffix) list and the options passed as arguments.
+//
+// The site of a host is its registrable domain, as defined by the HTML standard.
+// When the host has no registrable domain, because it's an IP address
+// or it's itself a public suffix, the site is the host.
+// The hosts are compared once normalized: in lowercase, encoded in ASCII and without the root dot.
+//
+// If the site of either host cannot be determined, such as when a host is blank,
+// the hosts are not the same site.
+func SameSiteFromListWithOptions(l *List, a, b string, options *FindOptions) bool {
+	sa, err := site(l, a, options)
+	if err != nil {
+		return false
+	}
+	sb, err := site(l, b, options)
+	if err != nil {
+		return false
+	}
+	return sa == sb
+}
+
+// SchemefulSameSite reports whether the URLs u1 and u2 have the same scheme
+// and their hosts belong to the same site using the default (Public Suffix) List.
+//
+// Examples:
+//
+//	u1, _ := url.Parse("https://www.example.com/")
+//	u2, _ := url.Parse("https://api.example.com:8443/")
+//	publicsuffix.SchemefulSameSite(u1, u2)
+//	// true
+//	u2, _ = url.Parse("http://api.example.com/")
+//	publicsuffix.SchemefulSameSite(u1, u2)
+//	// false
+func SchemefulSameSite(u1, u2 *url.URL) bool {
+	return SchemefulSameSiteFromListWithOptions(DefaultList, u1, u2, DefaultFindOptions)
+}
+
+// SchemefulSameSiteFromListWithOptions is like SchemefulSameSite,
+// but uses the (Public Suffix) list and the options passed as arguments.
+//
+// See SameSiteFromListWithOptions for the definition of site.
+// The ports are not compared.
+func SchemefulSameSiteFromListWithOptions(l *List, u1, u2 *url.URL, options *FindOptions) bool {
+	if u1.Scheme != u2.Scheme {
+		return false
+	}
+	return SameSiteFromListWithOptions(l, u1.Hostname(), u2.Hostname(), options)
+}
+
+// site returns the site of the host: the registrable domain or, when there is none, the host.
+func site(l *List, host string, options *FindOptions) (string, error) {
+	if ip, ok := parseIP(host); ok {
+		return ip.String(), nil
+	}
+
+	dn, err := ParseFromListWithOptions(l, host, options)
+	switch {
+	case err == nil:
+		return dn.SLD + "." + dn.TLD, nil
+	case errors.Is(err, ErrIsPublicSuffix), errors.Is(err, ErrNoRuleMatch):
+		n, _, err := normalize(host, options)
+		return n, err
+	default:
+		return "", err
+	}
+}
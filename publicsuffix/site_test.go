@@ -0,0 +1,100 @@
+package publicsuffix
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestSameSite(t *testing.T) {
+	testCases := []struct {
+		a, b string
+		want bool
+	}{
+		{"example.com", "example.com", true},
+		{"www.example.com", "example.com", true},
+		{"www.example.com", "api.example.com", true},
+		{"www.example.com", "www.example.org", false},
+		{"www.example.co.uk", "static.example.co.uk", true},
+		{"example.co.uk", "other.co.uk", false},
+
+		// private suffixes
+		{"foo.blogspot.com", "bar.blogspot.com", false},
+		{"www.foo.blogspot.com", "foo.blogspot.com", true},
+
+		// case, trailing dots and IDN
+		{"WWW.Example.COM", "example.com", true},
+		{"www.example.com.", "example.com", true},
+		{"www.食狮.公司.cn", "api.xn--85x722f.xn--55qx5d.cn", true},
+
+		// IP addresses are their own site
+		{"192.168.0.1", "192.168.0.1", true},
+		{"192.168.0.1", "192.168.0.2", false},
+		{"[::1]", "::1", true},
+		{"0.1", "192.168.0.1", false},
+		{"192.168.0.1.", "１９２.168.0.1", true},
+
+		// names that are only a suffix are their own site
+		{"co.uk", "co.uk", true},
+		{"co.uk", "example.co.uk", false},
+		{"blogspot.com", "blogspot.com", true},
+
+		// invalid hosts
+		{"", "", false},
+		{".example.com", ".example.com", false},
+	}
+
+	for _, testCase := range testCases {
+		if got := SameSite(testCase.a, testCase.b); got != testCase.want {
+			t.Errorf("SameSite(%v, %v) = %v, want %v", testCase.a, testCase.b, got, testCase.want)
+		}
+		if got := SameSite(testCase.b, testCase.a); got != testCase.want {
+			t.Errorf("SameSite(%v, %v) = %v, want %v", testCase.b, testCase.a, got, testCase.want)
+		}
+	}
+}
+
+func TestSameSiteFromListWithOptions(t *testing.T) {
+	options := &FindOptions{IgnorePrivate: true, DefaultRule: DefaultRule}
+	if !SameSiteFromListWithOptions(DefaultList, "foo.blogspot.com", "bar.blogspot.com", options) {
+		t.Errorf("SameSiteFromListWithOptions(foo.blogspot.com, bar.blogspot.com) = false, want true when ignoring private domains")
+	}
+
+	list := NewList()
+	_ = list.AddRule(MustNewRule("example"))
+	if SameSiteFromListWithOptions(list, "foo.example", "bar.example", nil) {
+		t.Errorf("SameSiteFromListWithOptions(foo.example, bar.example) = true, want false")
+	}
+	if !SameSiteFromListWithOptions(list, "www.foo.example", "foo.example", nil) {
+		t.Errorf("SameSiteFromListWithOptions(www.foo.example, foo.example) = false, want true")
+	}
+}
+
+func TestSchemefulSameSite(t *testing.T) {
+	testCases := []struct {
+		u1, u2 string
+		want   bool
+	}{
+		{"https://www.example.com/", "https://api.example.com:8443/path", true},
+		{"https://www.example.com/", "HTTPS://api.example.com/", true},
+		{"https://www.example.com/", "http://www.example.com/", false},
+		{"https://www.example.com/", "https://www.example.org/", false},
+		{"https://user@www.example.com/", "https://example.com/", true},
+		{"http://[::1]:8080/", "http://[::1]/", true},
+		{"file:///etc/hosts", "file:///etc/hosts", false},
+	}
+
+	for _, testCase := range testCases {
+		u1, err := url.Parse(testCase.u1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		u2, err := url.Parse(testCase.u2)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got := SchemefulSameSite(u1, u2); got != testCase.want {
+			t.Errorf("SchemefulSameSite(%v, %v) = %v, want %v", u1, u2, got, testCase.want)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package publicsuffix
+
+import (
+	"crypto/x509"
+	"errors"
+	"strings"
+)
+
+// CheckWildcardName checks whether the name, such as a wildcard pattern "*.example.com"
+// or a DNS name of a certificate, can be covered by a certificate.
+// It returns nil if the name is acceptable, otherwise a *WildcardError that says why.
+//
+// A wildcard must be the entire left-most label, and it must not cover a public suffix:
+// "*.example.co.uk" is accepted, "*.co.uk" and "*.github.io" are rejected.
+// Names without a wildcard are accepted, provided they can be parsed.
+//
+// The list defaults to DefaultList when nil, and the options to DefaultFindOptions.
+// Set FindOptions.IgnorePrivate to check only the ICANN suffixes, and accept wildcards
+// for the suffixes in the private section such as "*.github.io".
+func CheckWildcardName(name string, l *List, options *FindOptions) error {
+	if l == nil {
+		l = DefaultList
+	}
+
+	base := name
+	if strings.HasPrefix(name, "*.") {
+		base = name[2:]
+	}
+	if strings.IndexByte(base, '*') >= 0 {
+		return &WildcardError{Name: name, Err: ErrInvalidWildcard}
+	}
+
+	n, _, err := normalize(base, options)
+	if err != nil {
+		return &WildcardError{Name: name, Err: err}
+	}
+	if base != name && l.IsPublicSuffix(n, options) {
+		return &WildcardError{Name: name, Err: ErrWildcardPublicSuffix}
+	}
+	return nil
+}
+
+// CheckCertificate checks every DNS name of the certificate with CheckWildcardName,
+// as well as the Subject Common Name when it is a wildcard, as in legacy certificates.
+// It returns nil if every name is acceptable, otherwise the *WildcardErrors joined with errors.Join.
+func CheckCertificate(cert *x509.Certificate, l *List, options *FindOptions) error {
+	return checkNames(cert.Subject.CommonName, cert.DNSNames, l, options)
+}
+
+// CheckCertificateRequest is like CheckCertificate, but checks the names of a certificate request.
+func CheckCertificateRequest(csr *x509.CertificateRequest, l *List, options *FindOptions) error {
+	return checkNames(csr.Subject.CommonName, csr.DNSNames, l, options)
+}
+
+func checkNames(commonName string, names []string, l *List, options *FindOptions) error {
+	var errs []error
+	if strings.HasPrefix(commonName, "*") {
+		if err := CheckWildcardName(commonName, l, options); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	for _, name := range names {
+		if err := CheckWildcardName(name, l, options); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
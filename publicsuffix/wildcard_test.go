@@ -0,0 +1,111 @@
+package publicsuffix
+
+import (
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"testing"
+)
+
+func TestCheckWildcardName(t *testing.T) {
+	testCases := []struct {
+		name string
+		err  error
+	}{
+		{"*.example.com", nil},
+		{"*.example.co.uk", nil},
+		{"*.www.example.com", nil},
+		{"www.example.com", nil},
+		{"*.EXAMPLE.com", nil},
+		{"*.食狮.公司.cn", nil},
+
+		// wildcards covering a public suffix
+		{"*.com", ErrWildcardPublicSuffix},
+		{"*.co.uk", ErrWildcardPublicSuffix},
+		{"*.github.io", ErrWildcardPublicSuffix},
+		{"*.公司.cn", ErrWildcardPublicSuffix},
+		{"*.com.", ErrWildcardPublicSuffix},
+		// *.kobe.jp and !city.kobe.jp
+		{"*.example.kobe.jp", ErrWildcardPublicSuffix},
+		{"*.city.kobe.jp", nil},
+		// not listed
+		{"*.test", ErrWildcardPublicSuffix},
+		{"*.example.test", nil},
+
+		// names without a wildcard, including suffixes
+		{"co.uk", nil},
+		{"github.io", nil},
+
+		// misplaced wildcards
+		{"*", ErrInvalidWildcard},
+		{"foo.*.example.com", ErrInvalidWildcard},
+		{"f*.example.com", ErrInvalidWildcard},
+		{"*.*.example.com", ErrInvalidWildcard},
+
+		// invalid names
+		{"", ErrBlankName},
+		{"*.", ErrBlankName},
+	}
+
+	for _, testCase := range testCases {
+		err := CheckWildcardName(testCase.name, nil, nil)
+		if !errors.Is(err, testCase.err) {
+			t.Errorf("CheckWildcardName(%q) = %v, want %v", testCase.name, err, testCase.err)
+			continue
+		}
+
+		var werr *WildcardError
+		if err != nil && (!errors.As(err, &werr) || werr.Name != testCase.name) {
+			t.Errorf("CheckWildcardName(%q) error = %#v, want a *WildcardError", testCase.name, err)
+		}
+	}
+}
+
+func TestCheckWildcardName_IgnorePrivate(t *testing.T) {
+	options := &FindOptions{IgnorePrivate: true, DefaultRule: DefaultRule}
+
+	if err := CheckWildcardName("*.github.io", DefaultList, options); err != nil {
+		t.Errorf("CheckWildcardName(*.github.io) = %v, want nil when ignoring private domains", err)
+	}
+	if err := CheckWildcardName("*.co.uk", DefaultList, options); !errors.Is(err, ErrWildcardPublicSuffix) {
+		t.Errorf("CheckWildcardName(*.co.uk) = %v, want %v", err, ErrWildcardPublicSuffix)
+	}
+}
+
+func TestCheckCertificate(t *testing.T) {
+	cert := &x509.Certificate{
+		Subject:  pkix.Name{CommonName: "*.co.uk"},
+		DNSNames: []string{"example.com", "*.example.com", "*.github.io", "foo.*.example.com"},
+	}
+
+	err := CheckCertificate(cert, nil, nil)
+	for _, want := range []error{ErrWildcardPublicSuffix, ErrInvalidWildcard} {
+		if !errors.Is(err, want) {
+			t.Errorf("CheckCertificate() = %v, want %v", err, want)
+		}
+	}
+	if want := "name *.co.uk: wildcard covers a public suffix\n" +
+		"name *.github.io: wildcard covers a public suffix\n" +
+		"name foo.*.example.com: wildcard is not the left-most label"; err == nil || err.Error() != want {
+		t.Errorf("CheckCertificate() error message = %v, want %v", err, want)
+	}
+
+	cert = &x509.Certificate{
+		Subject:  pkix.Name{CommonName: "www.example.com"},
+		DNSNames: []string{"www.example.com", "*.example.com"},
+	}
+	if err := CheckCertificate(cert, nil, nil); err != nil {
+		t.Errorf("CheckCertificate() = %v, want nil", err)
+	}
+}
+
+func TestCheckCertificateRequest(t *testing.T) {
+	csr := &x509.CertificateRequest{DNSNames: []string{"*.example.co.uk", "*.blogspot.com"}}
+
+	if err := CheckCertificateRequest(csr, nil, nil); !errors.Is(err, ErrWildcardPublicSuffix) {
+		t.Errorf("CheckCertificateRequest() = %v, want %v", err, ErrWildcardPublicSuffix)
+	}
+	if err := CheckCertificateRequest(csr, DefaultList, &FindOptions{IgnorePrivate: true, DefaultRule: DefaultRule}); err != nil {
+		t.Errorf("CheckCertificateRequest() = %v, want nil when ignoring private domains", err)
+	}
+}
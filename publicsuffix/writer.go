@@ -0,0 +1,157 @@
+package publicsuffix
+
+import (
+	"bytes"
+	"io"
+	"slices"
+	"strings"
+)
+
+// DefaultWriterOptions are the default options used to write a Public Suffix list.
+var DefaultWriterOptions = &WriterOption{ASCIIEncoded: false}
+
+// WriterOption are the options you can use to customize the way a List
+// is written to the Public Suffix format.
+type WriterOption struct {
+	// Set to true to write the rules in A-labels (ASCII)
+	// as opposite to U-labels (Unicode).
+	// Default to false, which means the rules are written in Unicode,
+	// like in the original PSL.
+	ASCIIEncoded bool
+}
+
+// WriteTo writes the list to w in the Public Suffix format,
+// using the DefaultWriterOptions.
+// It implements io.WriterTo.
+func (l *List) WriteTo(w io.Writer) (int64, error) {
+	return l.WriteToWithOptions(w, nil)
+}
+
+// WriteToWithOptions writes the list to w in the Public Suffix format.
+//
+// The ICANN and the private rules are written in their own sections,
+// delimited by the ===BEGIN/END=== markers. Within each section the rules
+// that have an Owner are grouped under its comment block, in the order of the source.
+// The rules are sorted by their reversed labels, so that rules sharing a TLD are grouped together.
+// Loading the output with NewListFromString (with a matching ASCIIEncoded option)
+// results in a List with the same rules.
+func (l *List) WriteToWithOptions(w io.Writer, options *WriterOption) (int64, error) {
+	if options == nil {
+		options = DefaultWriterOptions
+	}
+
+	var icann, private []*Rule
+	l.walk(func(r *Rule) {
+		if r.Private {
+			private = append(private, r)
+		} else {
+			icann = append(icann, r)
+		}
+	})
+
+	buf := new(bytes.Buffer)
+	if err := writeSection(buf, icann, listMarkerBeginICANN, listMarkerEndICANN, options); err != nil {
+		return 0, err
+	}
+	if len(private) > 0 {
+		buf.WriteString("\n")
+		if err := writeSection(buf, private, listMarkerBeginPrivate, listMarkerEndPrivate, options); err != nil {
+			return 0, err
+		}
+	}
+
+	return buf.WriteTo(w)
+}
+
+func writeSection(buf *bytes.Buffer, rules []*Rule, begin, end string, options *WriterOption) error {
+	slices.SortFunc(rules, func(a, b *Rule) int {
+		if c := compareOwners(a.Owner, b.Owner); c != 0 {
+			return c
+		}
+		return compareRules(a, b)
+	})
+
+	buf.WriteString(begin + "\n")
+	var tld string
+	for i, r := range rules {
+		// separate each group of rules with a blank line,
+		// the rules without an owner are grouped by TLD
+		t := r.Value[strings.LastIndexByte(r.Value, '.')+1:]
+		if i == 0 || r.Owner != rules[i-1].Owner || (r.Owner == nil && t != tld) {
+			buf.WriteString("\n")
+			if r.Owner != nil {
+				for _, line := range strings.Split(r.Owner.Comment, "\n") {
+					buf.WriteString(listTokenComment + " " + line + "\n")
+				}
+			}
+		}
+		tld = t
+
+		value := r.Value
+		if !options.ASCIIEncoded {
+			var err error
+			if value, err = ToUnicode(value); err != nil {
+				return err
+			}
+		}
+		buf.WriteString(formatRule(r.Type, value) + "\n")
+	}
+	buf.WriteString("\n" + end + "\n")
+	return nil
+}
+
+// compareOwners sorts the owners in the order they appear in the source.
+// The rules without an owner come first.
+func compareOwners(a, b *Owner) int {
+	switch {
+	case a == b:
+		return 0
+	case a == nil:
+		return -1
+	case b == nil:
+		return 1
+	}
+	if c := a.Line - b.Line; c != 0 {
+		return c
+	}
+	if c := strings.Compare(a.Name, b.Name); c != 0 {
+		return c
+	}
+	return strings.Compare(a.Comment, b.Comment)
+}
+
+// compareRules sorts the rules by their reversed labels, then by type.
+func compareRules(a, b *Rule) int {
+	av, bv := a.Value, b.Value
+	for av != "" && bv != "" {
+		ai, bi := strings.LastIndexByte(av, '.'), strings.LastIndexByte(bv, '.')
+		if c := strings.Compare(av[ai+1:], bv[bi+1:]); c != 0 {
+			return c
+		}
+		av, bv = av[:max(ai, 0)], bv[:max(bi, 0)]
+	}
+	switch {
+	case av == "" && bv != "":
+		return -1
+	case av != "" && bv == "":
+		return 1
+	default:
+		return a.Type - b.Type
+	}
+}
+
+// formatRule returns the Public Suffix representation of a rule
+// of type t for value, such as "*.uk" or "!parliament.uk".
+func formatRule(t int, value string) string {
+	switch t {
+	case WildcardType:
+		if value == "" {
+			return "*"
+		}
+		return "*." + value
+	case ExceptionType:
+		return "!" + value
+	default:
+		return value
+	}
+}
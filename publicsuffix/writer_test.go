@@ -0,0 +1,183 @@
+package publicsuffix
+
+import (
+	"bytes"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestListWriteTo(t *testing.T) {
+	src := `
+// ===BEGIN ICANN DOMAINS===
+com
+*.kobe.jp
+!city.kobe.jp
+мкд
+jp
+// ===END ICANN DOMAINS===
+// ===BEGIN PRIVATE DOMAINS===
+blogspot.com
+// ===END PRIVATE DOMAINS===
+`
+	list, err := NewListFromString(src, nil)
+	if err != nil {
+		t.Fatalf("Parse returned an error: %v", err)
+	}
+
+	testCases := []struct {
+		options *WriterOption
+		want    string
+	}{
+		{nil, `// ===BEGIN ICANN DOMAINS===
+
+com
+
+jp
+*.kobe.jp
+!city.kobe.jp
+
+мкд
+
+// ===END ICANN DOMAINS===
+
+// ===BEGIN PRIVATE DOMAINS===
+
+blogspot.com
+
+// ===END PRIVATE DOMAINS===
+`},
+		{&WriterOption{ASCIIEncoded: true}, `// ===BEGIN ICANN DOMAINS===
+
+com
+
+jp
+*.kobe.jp
+!city.kobe.jp
+
+xn--d1alf
+
+// ===END ICANN DOMAINS===
+
+// ===BEGIN PRIVATE DOMAINS===
+
+blogspot.com
+
+// ===END PRIVATE DOMAINS===
+`},
+	}
+
+	for _, testCase := range testCases {
+		buf := new(bytes.Buffer)
+		n, err := list.WriteToWithOptions(buf, testCase.options)
+		if err != nil {
+			t.Fatalf("WriteToWithOptions returned an error: %v", err)
+		}
+		if want, got := int64(buf.Len()), n; want != got {
+			t.Errorf("WriteToWithOptions returned %v bytes written, want %v", got, want)
+		}
+		if want, got := testCase.want, buf.String(); want != got {
+			t.Errorf("WriteToWithOptions wrote\n%v\nwant\n%v", got, want)
+		}
+	}
+}
+
+func TestListWriteTo_RoundTrip(t *testing.T) {
+	testCases := []*WriterOption{
+		{ASCIIEncoded: false},
+		{ASCIIEncoded: true},
+	}
+
+	want := sortedRules(DefaultList)
+	for _, options := range testCases {
+		buf := new(strings.Builder)
+		if _, err := DefaultList.WriteToWithOptions(buf, options); err != nil {
+			t.Fatalf("WriteToWithOptions returned an error: %v", err)
+		}
+
+		list, err := NewListFromString(buf.String(), &ParserOption{PrivateDomains: true, ASCIIEncoded: options.ASCIIEncoded})
+		if err != nil {
+			t.Fatalf("Parse returned an error: %v", err)
+		}
+		if got := sortedRules(list); !reflect.DeepEqual(want, got) {
+			t.Errorf("WriteToWithOptions(%+v) did not round-trip: got %v rules, want %v", options, len(got), len(want))
+		}
+	}
+}
+
+// sortedRules returns the rules in the list, without the owners
+// because the line numbers change when a list is written.
+func TestListWriteTo_Owners(t *testing.T) {
+	src := `// ===BEGIN ICANN DOMAINS===
+
+// jp : https://en.wikipedia.org/wiki/.jp
+jp
+*.kobe.jp
+// ===END ICANN DOMAINS===
+// ===BEGIN PRIVATE DOMAINS===
+
+// Google, Inc.
+blogspot.com
+
+// Amazon : https://www.amazon.com/
+// Submitted by AWS Security <psl-maintainers@amazon.com>
+s3.amazonaws.com
+// ===END PRIVATE DOMAINS===
+`
+	list, err := NewListFromString(src, nil)
+	if err != nil {
+		t.Fatalf("Parse returned an error: %v", err)
+	}
+	_ = list.AddRule(MustNewRule("com"))
+
+	want := `// ===BEGIN ICANN DOMAINS===
+
+com
+
+// jp : https://en.wikipedia.org/wiki/.jp
+jp
+*.kobe.jp
+
+// ===END ICANN DOMAINS===
+
+// ===BEGIN PRIVATE DOMAINS===
+
+// Google, Inc.
+blogspot.com
+
+// Amazon : https://www.amazon.com/
+// Submitted by AWS Security <psl-maintainers@amazon.com>
+s3.amazonaws.com
+
+// ===END PRIVATE DOMAINS===
+`
+	buf := new(strings.Builder)
+	if _, err := list.WriteTo(buf); err != nil {
+		t.Fatalf("WriteTo returned an error: %v", err)
+	}
+	if got := buf.String(); want != got {
+		t.Errorf("WriteTo wrote\n%v\nwant\n%v", got, want)
+	}
+
+	reloaded, err := NewListFromString(buf.String(), nil)
+	if err != nil {
+		t.Fatalf("Parse returned an error: %v", err)
+	}
+	if want, got := "Amazon", reloaded.Owner("s3.amazonaws.com").Name; want != got {
+		t.Errorf("Owner(%v) = %v, want %v", "s3.amazonaws.com", got, want)
+	}
+}
+
+func sortedRules(l *List) []Rule {
+	var rules []Rule
+	for _, r := range listRules(l) {
+		rule := *r
+		rule.Owner = nil
+		rules = append(rules, rule)
+	}
+	slices.SortFunc(rules, func(a, b Rule) int {
+		return compareRules(&a, &b)
+	})
+	return rules
+}